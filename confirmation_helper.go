@@ -1,21 +1,21 @@
 package styledconsole
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strings"
-
-	"golang.org/x/term"
 )
 
-func askConfirm(label string, defaultAnswer *bool) (bool, error) {
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+func (c *Console) askConfirm(ctx context.Context, label string, defaultAnswer *bool) (bool, error) {
+	if !c.isTerm() {
 		return false, errors.New("cannot open a prompt outside of a terminal")
 	}
 
+	promptCtx, cancel := c.promptContext(ctx)
+	defer cancel()
+
 	for {
 		var options string
 		if defaultAnswer != nil {
@@ -27,23 +27,36 @@ func askConfirm(label string, defaultAnswer *bool) (bool, error) {
 		} else {
 			options = "y/n"
 		}
-		fmt.Printf("%s [%s]: ", greenStyle.Apply(strings.TrimSpace(label)), yellowStyle.Apply(options))
+		prompt := fmt.Sprintf("%s [%s]: ", c.applyStyle(greenStyle, strings.TrimSpace(label)), c.applyStyle(yellowStyle, options))
+		fmt.Fprint(c.out, prompt)
 
-		reader := bufio.NewReader(os.Stdin)
-		textAnswer, err := reader.ReadString('\n')
+		textAnswer, err := c.readShortAnswer(promptCtx, prompt)
 
 		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			if isAnswerTimeout(ctx, err) {
+				if defaultAnswer != nil {
+					return *defaultAnswer, nil
+				}
+				return false, ErrTimeout
+			}
 			if err == io.EOF && defaultAnswer != nil {
 				return *defaultAnswer, nil
 			}
+			if isLeftPrompt(err) {
+				return false, c.handleInterrupt(err)
+			}
 			return false, fmt.Errorf("there was an error reading the stdin: %w", err)
 		}
 
-		if strings.ToLower(textAnswer) == "yes\n" || strings.ToLower(textAnswer) == "y\n" {
+		textAnswer = strings.ToLower(strings.TrimSpace(textAnswer))
+		if textAnswer == "yes" || textAnswer == "y" {
 			return true, nil
-		} else if strings.ToLower(textAnswer) == "no\n" || strings.ToLower(textAnswer) == "n\n" {
+		} else if textAnswer == "no" || textAnswer == "n" {
 			return false, nil
-		} else if textAnswer == "\n" && defaultAnswer != nil {
+		} else if textAnswer == "" && defaultAnswer != nil {
 			return *defaultAnswer, nil
 		}
 	}
@@ -0,0 +1,530 @@
+package styledconsole
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"golang.org/x/term"
+)
+
+var defaultConsole = New(os.Stdin, os.Stdout)
+
+// ErrTimeout is returned by the prompts without a default answer, when the user did not answer before the timeout
+// given to WithAnswerTimeout()
+var ErrTimeout = errors.New("no answer was given before the timeout")
+
+// ErrInterrupted is returned by the prompts when the user pressed Ctrl-C
+var ErrInterrupted = errors.New("the prompt was interrupted")
+
+// ErrAborted is returned by the prompts when the user pressed Ctrl-D instead of answering
+var ErrAborted = errors.New("the prompt was aborted")
+
+// Console reads the answers of the user from an io.Reader, and prints styled output to an io.Writer.
+// The package-level functions use a Console bound to the standard input and output.
+type Console struct {
+	keys    *KeyDecoder
+	inFile  fileDescriptor
+	out     io.Writer
+	outFile fileDescriptor
+	printer *styledprinter.Printer
+
+	width      int
+	height     int
+	isTerminal *bool
+	colorLevel *styledprinter.ColorLevel
+	mouse      bool
+
+	answerTimeout   time.Duration
+	interruptSignal bool
+
+	progressMutex sync.Mutex
+	progressBar   *ProgressBar
+
+	liveMutex   sync.Mutex
+	liveWidgets []liveWidget
+
+	history       []string
+	historyFile   string
+	historyLoaded bool
+}
+
+// Option customizes the behavior of a Console
+type Option func(*Console)
+
+// fileDescriptor is implemented by the readers and writers that are backed by a file, such as *os.File
+type fileDescriptor interface {
+	Fd() uintptr
+}
+
+// WithSize forces the size of the terminal instead of detecting it from the output.
+func WithSize(width int, height int) Option {
+	return func(c *Console) {
+		c.width = width
+		c.height = height
+	}
+}
+
+// WithTerminal forces the console to consider (or not) that it is connected to a terminal.
+// This is useful when the output is not a file, like an SSH session, but can still handle interactive prompts.
+func WithTerminal(isTerminal bool) Option {
+	return func(c *Console) {
+		c.isTerminal = &isTerminal
+	}
+}
+
+// WithColorLevel overrides the range of colors the console can use, instead of detecting it from the output.
+func WithColorLevel(level styledprinter.ColorLevel) Option {
+	return func(c *Console) {
+		c.colorLevel = &level
+	}
+}
+
+// WithMouse lets the user click on the choices of the Choice and MultiChoice prompts, and scroll them with the wheel.
+// While such a prompt is displayed, the text of the terminal cannot be selected with the mouse.
+func WithMouse(enabled bool) Option {
+	return func(c *Console) {
+		c.mouse = enabled
+	}
+}
+
+// WithAnswerTimeout limits the time the user has to answer a prompt. Once it is over, the default answer of the prompt
+// is returned, or ErrTimeout if there is none. This is useful for scripts that can run unattended.
+// The input is not read anymore once a prompt is over, except on Windows, see NewKeyDecoder().
+func WithAnswerTimeout(timeout time.Duration) Option {
+	return func(c *Console) {
+		c.answerTimeout = timeout
+	}
+}
+
+// WithInterruptSignal makes the prompts send an interrupt signal to the program when the user presses Ctrl-C,
+// once the terminal is restored, as a shell would do. ErrInterrupted is still returned if the signal is handled.
+func WithInterruptSignal(enabled bool) Option {
+	return func(c *Console) {
+		c.interruptSignal = enabled
+	}
+}
+
+// New instanciates a Console that reads from in and writes to out.
+// The colors are adapted to what the output supports, see styledprinter.DetectColorLevel().
+func New(in io.Reader, out io.Writer, opts ...Option) *Console {
+	c := &Console{
+		keys:    NewKeyDecoder(in),
+		out:     out,
+		printer: styledprinter.NewPrinter(out),
+	}
+	c.inFile, _ = in.(fileDescriptor)
+	c.outFile, _ = out.(fileDescriptor)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.width > 0 {
+		c.printer.SetWidth(c.width)
+	}
+	if c.colorLevel != nil {
+		c.printer.SetColorLevel(*c.colorLevel)
+	}
+
+	return c
+}
+
+// SetColorLevel overrides the range of colors the console can use.
+// Use styledprinter.ColorLevelNone to disable all styles.
+func (c *Console) SetColorLevel(level styledprinter.ColorLevel) {
+	c.printer.SetColorLevel(level)
+}
+
+// SetMouse enables or disables the mouse in the Choice and MultiChoice prompts, see WithMouse().
+func (c *Console) SetMouse(enabled bool) {
+	c.mouse = enabled
+}
+
+// SetAnswerTimeout limits the time the user has to answer a prompt, see WithAnswerTimeout(). A timeout of 0 disables it.
+func (c *Console) SetAnswerTimeout(timeout time.Duration) {
+	c.answerTimeout = timeout
+}
+
+// SetInterruptSignal enables or disables the interrupt signal sent when Ctrl-C is pressed in a prompt, see WithInterruptSignal().
+func (c *Console) SetInterruptSignal(enabled bool) {
+	c.interruptSignal = enabled
+}
+
+// handleInterrupt sends the interrupt signal to the program if the prompt was interrupted and the console is configured to do so.
+// It must be called once the terminal is restored.
+func (c *Console) handleInterrupt(err error) error {
+	if errors.Is(err, ErrInterrupted) && c.interruptSignal {
+		sendInterruptSignal()
+	}
+
+	return err
+}
+
+// isLeftPrompt returns whether the user left the prompt with Ctrl-C or Ctrl-D
+func isLeftPrompt(err error) bool {
+	return errors.Is(err, ErrInterrupted) || errors.Is(err, ErrAborted)
+}
+
+// promptContext applies the answer timeout of the console to the context of a prompt
+func (c *Console) promptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.answerTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.answerTimeout)
+}
+
+// isAnswerTimeout returns whether a prompt was stopped by the answer timeout of the console rather than by its own context
+func isAnswerTimeout(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// Keys returns the decoder of the keys typed in the input of the console, to build custom interactive widgets.
+// The input must be put in raw mode, for example with term.MakeRaw(), for the keys to be received as soon as they are typed.
+func (c *Console) Keys() *KeyDecoder {
+	return c.keys
+}
+
+// isTerm returns true if the output of the console is a terminal
+func (c *Console) isTerm() bool {
+	if c.isTerminal != nil {
+		return *c.isTerminal
+	}
+
+	return c.outFile != nil && term.IsTerminal(int(c.outFile.Fd()))
+}
+
+// makeRaw puts the input of the console in raw mode if it is a terminal, and enables the bracketed paste mode of the output.
+// It returns a function to restore their state, that can safely be called several times.
+func (c *Console) makeRaw() (func(), error) {
+	restoreInput := func() {}
+	if c.inFile != nil && term.IsTerminal(int(c.inFile.Fd())) {
+		fd := int(c.inFile.Fd())
+		oldState, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, err
+		}
+		restoreInput = func() { _ = term.Restore(fd, oldState) }
+	}
+
+	if !c.isTerm() {
+		return restoreInput, nil
+	}
+
+	// The pasted text is surrounded with escape sequences, so that its line breaks are not taken for the Enter key
+	c.enableBracketedPaste()
+	restored := false
+	return func() {
+		if !restored {
+			restored = true
+			c.disableBracketedPaste()
+			restoreInput()
+		}
+	}, nil
+}
+
+// Section displays the given string as the title of some command section.
+func (c *Console) Section(title string) {
+	titleLen := styledprinter.Width(title)
+	underline := strings.Repeat("=", titleLen)
+
+	c.printAbove(func() {
+		c.printer.Write(fmt.Sprintf("<fg=yellow;options=bold>%s\n%s\n</>", title, underline), true)
+	})
+}
+
+// Text displays the given string as regular text. This is useful to render help messages and instructions for the user running the command.
+// This methods support style tags such as "<fg=blue>blue text</>".
+func (c *Console) Text(content string) {
+	c.printAbove(func() {
+		c.printer.Write(content, true)
+	})
+}
+
+// Listing displays an list of elements
+func (c *Console) Listing(items []string) {
+	c.printAbove(func() {
+		for _, item := range items {
+			c.printer.WriteIndented(fmt.Sprintf(" <fg=yellow>*</> %s", item), 3, true)
+		}
+	})
+}
+
+// Table pretty-prints a table with headers. The cells can contain line breaks and style tags.
+func (c *Console) Table(headers []string, rows [][]string) {
+	c.TableWithOptions(headers, rows, TableOptions{})
+}
+
+// TableWithOptions is the same as Table() but the style of the borders, as well as the alignment and the width of each column can be customized.
+func (c *Console) TableWithOptions(headers []string, rows [][]string, options TableOptions) {
+	termWidth, _ := c.getWinsize()
+
+	c.printAbove(func() {
+		fmt.Fprintf(c.out, "%s\n", formatTable(headers, rows, options, termWidth, c.printer.ColorLevel()))
+	})
+}
+
+// NewLine prints a line break.
+func (c *Console) NewLine() {
+	c.printAbove(func() {
+		c.printer.Write("", true)
+	})
+}
+
+// NewLines print the given amount of new breaks.
+func (c *Console) NewLines(newLineCount int) {
+	if newLineCount > 0 {
+		c.printAbove(func() {
+			c.printer.Write(strings.Repeat("\n", newLineCount-1), true)
+		})
+	}
+}
+
+// Ask prompts a question with the given label.
+// A function can be given to ensure the validity of the response. To allow any response (even empty), put nil as validator.
+func (c *Console) Ask(label string, validator func(string) bool) (string, error) {
+	return c.AskContext(context.Background(), label, validator)
+}
+
+// AskContext is the same as Ask() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func (c *Console) AskContext(ctx context.Context, label string, validator func(string) bool) (string, error) {
+	q := question{
+		Label:         label,
+		IsClosed:      false,
+		IsHidden:      false,
+		DefaultAnswer: "",
+		Validator:     validator,
+	}
+
+	res, err := c.askQuestion(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// AskWithDefault is the same as Ask() but if the user's answer is empty, the given defaultAnswer is chosen instead.
+func (c *Console) AskWithDefault(label string, defaultAnswer string, validator func(string) bool) (string, error) {
+	return c.AskWithDefaultContext(context.Background(), label, defaultAnswer, validator)
+}
+
+// AskWithDefaultContext is the same as AskWithDefault() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func (c *Console) AskWithDefaultContext(ctx context.Context, label string, defaultAnswer string, validator func(string) bool) (string, error) {
+	q := question{
+		Label:         label,
+		IsClosed:      false,
+		IsHidden:      false,
+		DefaultAnswer: defaultAnswer,
+		Validator:     validator,
+	}
+
+	res, err := c.askQuestion(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// AskWithCompletion is the same as Ask() but the answer can be completed with the Tab key, which cycles through the
+// candidates returned by the completer for the current input. The first candidate that starts with the input is suggested
+// as a grayed text, that is accepted with Enter or the right arrow, and dismissed with Delete.
+// PathCompleter can be used to complete the paths of the filesystem.
+func (c *Console) AskWithCompletion(label string, completer func(input string) []string) (string, error) {
+	return c.AskWithCompletionContext(context.Background(), label, completer)
+}
+
+// AskWithCompletionContext is the same as AskWithCompletion() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func (c *Console) AskWithCompletionContext(ctx context.Context, label string, completer func(input string) []string) (string, error) {
+	q := question{
+		Label:         label,
+		IsClosed:      false,
+		IsHidden:      false,
+		DefaultAnswer: "",
+		Completer:     completer,
+	}
+
+	res, err := c.askQuestion(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// AskHidden is the same as Ask() but the characters typed by the user are not printed in the output, in a linux-style password prompt.
+func (c *Console) AskHidden(label string, validator func(string) bool) (string, error) {
+	return c.AskHiddenContext(context.Background(), label, validator)
+}
+
+// AskHiddenContext is the same as AskHidden() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func (c *Console) AskHiddenContext(ctx context.Context, label string, validator func(string) bool) (string, error) {
+	q := question{
+		Label:         label,
+		IsClosed:      false,
+		IsHidden:      true,
+		DefaultAnswer: "",
+		Validator:     validator,
+	}
+
+	res, err := c.askQuestion(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// Confirm prompts a yes/no question.
+func (c *Console) Confirm(label string) (bool, error) {
+	return c.ConfirmContext(context.Background(), label)
+}
+
+// ConfirmContext is the same as Confirm() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func (c *Console) ConfirmContext(ctx context.Context, label string) (bool, error) {
+	return c.askConfirm(ctx, label, nil)
+}
+
+// ConfirmWithDefault prompts a yes/no question, with a given answer by default if the user's answer is empty.
+func (c *Console) ConfirmWithDefault(label string, defaultAnswer bool) (bool, error) {
+	return c.ConfirmWithDefaultContext(context.Background(), label, defaultAnswer)
+}
+
+// ConfirmWithDefaultContext is the same as ConfirmWithDefault() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func (c *Console) ConfirmWithDefaultContext(ctx context.Context, label string, defaultAnswer bool) (bool, error) {
+	return c.askConfirm(ctx, label, &defaultAnswer)
+}
+
+// Choice prints a list of choices the user can choose between.
+// The prompts adapts itself to the size of the terminal, and typing filters the choices with a fuzzy search.
+func (c *Console) Choice(label string, choices []string) (string, error) {
+	return c.ChoiceContext(context.Background(), label, choices)
+}
+
+// ChoiceContext is the same as Choice() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func (c *Console) ChoiceContext(ctx context.Context, label string, choices []string) (string, error) {
+	q := question{
+		Label:         label,
+		IsClosed:      true,
+		Choices:       choices,
+		DefaultChoice: -1,
+	}
+
+	choice, err := c.askQuestion(ctx, q)
+	if err != nil {
+		return "", err
+	}
+
+	return choice, nil
+}
+
+// ChoiceWithDefault is the same as Choice() but a specific answer index should be given to highlight by default.
+func (c *Console) ChoiceWithDefault(label string, choices []string, defaultAnswer int) (string, error) {
+	return c.ChoiceWithDefaultContext(context.Background(), label, choices, defaultAnswer)
+}
+
+// ChoiceWithDefaultContext is the same as ChoiceWithDefault() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func (c *Console) ChoiceWithDefaultContext(ctx context.Context, label string, choices []string, defaultAnswer int) (string, error) {
+	q := question{
+		Label:         label,
+		IsClosed:      true,
+		Choices:       choices,
+		DefaultChoice: defaultAnswer,
+	}
+
+	choice, err := c.askQuestion(ctx, q)
+	if err != nil {
+		return "", err
+	}
+
+	return choice, nil
+}
+
+// MultiChoice prints a list of choices the user can select several of. The space key toggles the highlighted choice,
+// "a" selects or deselects all the choices, and Enter confirms the selection.
+// The indexes of the choices that are selected at first are given in defaults, which can be nil.
+func (c *Console) MultiChoice(label string, choices []string, defaults []int) ([]string, error) {
+	return c.MultiChoiceContext(context.Background(), label, choices, defaults)
+}
+
+// MultiChoiceContext is the same as MultiChoice() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func (c *Console) MultiChoiceContext(ctx context.Context, label string, choices []string, defaults []int) ([]string, error) {
+	return c.MultiChoiceWithLimitsContext(ctx, label, choices, defaults, 0, 0)
+}
+
+// MultiChoiceWithLimits is the same as MultiChoice() but the user must select between min and max choices.
+// A max of 0 allows to select all the choices.
+func (c *Console) MultiChoiceWithLimits(label string, choices []string, defaults []int, min int, max int) ([]string, error) {
+	return c.MultiChoiceWithLimitsContext(context.Background(), label, choices, defaults, min, max)
+}
+
+// MultiChoiceWithLimitsContext is the same as MultiChoiceWithLimits() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func (c *Console) MultiChoiceWithLimitsContext(ctx context.Context, label string, choices []string, defaults []int, min int, max int) ([]string, error) {
+	selected, err := c.askMultipleChoices(ctx, label, choices, defaults, min, max)
+	return selected, c.handleInterrupt(err)
+}
+
+// Success displays the given string highlighted as a successful message (with a green background and an [OK] label).
+func (c *Console) Success(content string) {
+	c.printAbove(func() {
+		c.printer.WriteBlock(fmt.Sprintf("Success:\n%s", content), "  ", "bg=green;fg=black", true)
+	})
+}
+
+// Warning displays the given string highlighted as a warning message (with yellow text and a [Warning] label).
+func (c *Console) Warning(content string) {
+	c.printAbove(func() {
+		c.printer.WriteBlock(fmt.Sprintf("Warning:\n%s", content), "# ", "fg=yellow", true)
+	})
+}
+
+// Error displays the given string highlighted as an error message (with a red background and the [Error] label).
+func (c *Console) Error(content string) {
+	c.printAbove(func() {
+		c.printer.WriteBlock(fmt.Sprintf("Error:\n%s", content), "  ", "bg=red;fg=black", true)
+	})
+}
+
+// readPassword reads a line from the input of the console without echoing it
+func (c *Console) readPassword(ctx context.Context) ([]byte, error) {
+	restoreTerminal, err := c.makeRaw()
+	if err != nil {
+		return nil, fmt.Errorf("there was an error switching terminal to raw mode: %w", err)
+	}
+	defer restoreTerminal()
+
+	password := []rune{}
+	for {
+		key, err := c.keys.ReadKeyContext(ctx)
+		if err != nil {
+			return []byte(string(password)), err
+		}
+
+		switch {
+		case key.Key == KeyEnter:
+			return []byte(string(password)), nil
+		case key.Key == KeyBackspace:
+			if len(password) > 0 {
+				password = password[:len(password)-1]
+			}
+		case key.isCtrl('u'):
+			password = password[:0]
+		case key.isCtrl('d') && len(password) == 0:
+			return nil, ErrAborted
+		case key.isCtrl('c'):
+			return nil, ErrInterrupted
+		case key.Key == KeyPaste:
+			password = append(password, key.pastedRunes(``)...)
+		case key.isCharacter():
+			password = append(password, key.Rune)
+		}
+	}
+}
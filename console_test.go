@@ -0,0 +1,208 @@
+package styledconsole
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConsoleOutput checks the console writes to the given io.Writer
+func TestConsoleOutput(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithSize(40, 20))
+
+	console.Text("some text")
+	console.Table([]string{"one", "two"}, [][]string{{"1", "2"}})
+
+	assert.Equal(
+		"some text\n+-----+-----+\n| one | two |\n+-----+-----+\n| 1   | 2   |\n+-----+-----+\n",
+		out.String(),
+	)
+}
+
+// TestConsoleInput checks the console reads the answers from the given io.Reader
+func TestConsoleInput(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader("my answer\nmaybe\ny\nsex\x7fcret\n"), out, WithTerminal(true))
+
+	answer, err := console.Ask("Question", nil)
+	assert.Nil(err)
+	assert.Equal("my answer", answer)
+
+	confirmed, err := console.Confirm("Confirm")
+	assert.Nil(err)
+	assert.True(confirmed)
+
+	// The bytes read ahead by a prompt are kept for the next one
+	password, err := console.AskHidden("Password", nil)
+	assert.Nil(err)
+	assert.Equal("secret", password)
+
+	// Without a terminal, prompts are refused
+	console = New(strings.NewReader("my answer\n"), out)
+	_, err = console.Ask("Question", nil)
+	assert.NotNil(err)
+}
+
+// TestConsoleColorLevel checks the styles are only applied when the console supports colors
+func TestConsoleColorLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithColorLevel(styledprinter.ColorLevelNone))
+	console.Text("<fg=red>red text</>")
+	assert.Equal("red text\n", out.String())
+
+	out.Reset()
+	console.SetColorLevel(styledprinter.ColorLevel16)
+	console.Text("<fg=red>red text</>")
+	assert.Equal("\033[31mred text\033[39m\n", out.String())
+}
+
+// TestPromptContext checks the prompts are stopped when their context is cancelled, and the terminal is restored
+func TestPromptContext(t *testing.T) {
+	assert := assert.New(t)
+
+	// Nothing is ever typed
+	reader, _ := io.Pipe()
+	out := &bytes.Buffer{}
+	console := New(reader, out, WithTerminal(true), WithSize(40, 20))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := console.AskContext(ctx, "Question", nil)
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.True(strings.HasSuffix(out.String(), "\r\n\033[?2004l"))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	out.Reset()
+	_, err = console.ChoiceContext(ctx, "Choice", []string{"one", "two", "three"})
+	assert.Equal(context.Canceled, err)
+	assert.True(strings.HasSuffix(out.String(), "\033[?25h\033[?0c\033[?2004l"), "the cursor is shown again")
+
+	_, err = console.ConfirmWithDefaultContext(ctx, "Confirm", true)
+	assert.Equal(context.Canceled, err)
+	_, err = console.MultiChoiceContext(ctx, "Choices", []string{"one", "two"}, nil)
+	assert.Equal(context.Canceled, err)
+}
+
+// TestAnswerTimeout checks the default answers are returned once the answer timeout is over
+func TestAnswerTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, _ := io.Pipe()
+	output := &bytes.Buffer{}
+	console := New(reader, output, WithTerminal(true), WithSize(40, 20), WithAnswerTimeout(20*time.Millisecond))
+
+	answer, err := console.AskWithDefault("Question", "default", nil)
+	assert.Nil(err)
+	assert.Equal("default", answer)
+
+	confirmed, err := console.ConfirmWithDefault("Confirm", true)
+	assert.Nil(err)
+	assert.True(confirmed)
+
+	choice, err := console.ChoiceWithDefault("Choice", []string{"one", "two", "three"}, 2)
+	assert.Nil(err)
+	assert.Equal("three", choice)
+
+	choices, err := console.MultiChoiceWithLimits("Choices", []string{"one", "two", "three"}, []int{0, 2}, 1, 0)
+	assert.Nil(err)
+	assert.Equal([]string{"one", "three"}, choices)
+
+	// Without a default answer, the prompt fails
+	_, err = console.Ask("Question", nil)
+	assert.Equal(ErrTimeout, err)
+	_, err = console.Choice("Choice", []string{"one", "two"})
+	assert.Equal(ErrTimeout, err)
+	_, err = console.ChoiceWithDefault("Choice", []string{"one", "two"}, -1)
+	assert.Equal(ErrTimeout, err)
+	assert.NotContains(output.String(), "error:", "the error is left to the caller")
+	_, err = console.MultiChoiceWithLimits("Choices", []string{"one", "two"}, nil, 1, 0)
+	assert.Equal(ErrTimeout, err)
+
+	// The timeout also applies while waiting for a terminal big enough to display the list
+	small := New(reader, &bytes.Buffer{}, WithTerminal(true), WithSize(10, 2), WithAnswerTimeout(20*time.Millisecond))
+	choices, err = small.MultiChoice("Choices", []string{"one", "two", "three"}, []int{1})
+	assert.Nil(err)
+	assert.Equal([]string{"two"}, choices)
+	_, err = small.MultiChoiceWithLimits("Choices", []string{"one", "two"}, nil, 1, 0)
+	assert.Equal(ErrTimeout, err)
+	choice, err = small.ChoiceWithDefault("Choice", []string{"one", "two", "three"}, 1)
+	assert.Nil(err)
+	assert.Equal("two", choice)
+
+	// The cancellation of the context is not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = console.AskWithDefaultContext(ctx, "Question", "default", nil)
+	assert.Equal(context.Canceled, err)
+}
+
+// TestPromptInterrupt checks Ctrl-C and Ctrl-D stop the prompts with an error, once the terminal is restored
+func TestPromptInterrupt(t *testing.T) {
+	assert := assert.New(t)
+
+	prompts := map[string]func(*Console) error{
+		"Ask": func(c *Console) error {
+			_, err := c.Ask("Question", nil)
+			return err
+		},
+		"AskHidden": func(c *Console) error {
+			_, err := c.AskHidden("Password", nil)
+			return err
+		},
+		"Confirm": func(c *Console) error {
+			_, err := c.Confirm("Confirm")
+			return err
+		},
+		"Choice": func(c *Console) error {
+			_, err := c.Choice("Choice", []string{"one", "two"})
+			return err
+		},
+		"MultiChoice": func(c *Console) error {
+			_, err := c.MultiChoice("Choices", []string{"one", "two"}, nil)
+			return err
+		},
+	}
+	for name, prompt := range prompts {
+		output := &bytes.Buffer{}
+		err := prompt(New(strings.NewReader("ab\x03"), output, WithTerminal(true), WithSize(40, 20)))
+		assert.Equal(ErrInterrupted, err, name)
+		assert.Contains(output.String(), "\033[?2004l", name)
+
+		err = prompt(New(strings.NewReader("\x04"), &bytes.Buffer{}, WithTerminal(true), WithSize(40, 20)))
+		assert.Equal(ErrAborted, err, name)
+	}
+
+	// Ctrl-D only aborts an empty answer
+	answer, err := New(strings.NewReader("ab\x01\x04\n"), &bytes.Buffer{}, WithTerminal(true)).Ask("Question", nil)
+	assert.Nil(err)
+	assert.Equal("b", answer)
+}
+
+// TestPromptPanic checks the terminal is restored when a prompt panics
+func TestPromptPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	output := &bytes.Buffer{}
+	console := New(strings.NewReader("a\n"), output, WithTerminal(true))
+	assert.Panics(func() {
+		_, _ = console.AskWithCompletion("Question", func(string) []string {
+			panic("completion failed")
+		})
+	})
+	assert.True(strings.HasSuffix(output.String(), "\033[?2004l"))
+}
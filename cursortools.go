@@ -5,16 +5,43 @@ import (
 )
 
 // hideCursor hides the cursor, can be reversed with showCursor()
-func hideCursor() {
-	fmt.Print("\033[?25l")
+func (c *Console) hideCursor() {
+	fmt.Fprint(c.out, "\033[?25l")
 }
 
 // showCursor restores the cursor after it was hidden
-func showCursor() {
-	fmt.Print("\033[?25h\033[?0c")
+func (c *Console) showCursor() {
+	fmt.Fprint(c.out, "\033[?25h\033[?0c")
 }
 
 // clearWindowFromCursor clears all the output from the cursors' current position to the end of the screen.
-func clearWindowFromCursor() {
-	fmt.Print("\033[0J")
+func (c *Console) clearWindowFromCursor() {
+	fmt.Fprint(c.out, "\033[0J")
+}
+
+// enableBracketedPaste asks the terminal to surround the pasted text with escape sequences, can be reversed with disableBracketedPaste()
+func (c *Console) enableBracketedPaste() {
+	fmt.Fprint(c.out, "\033[?2004h")
+}
+
+// disableBracketedPaste restores the default behavior of the terminal, where the pasted text is sent as if it were typed
+func (c *Console) disableBracketedPaste() {
+	fmt.Fprint(c.out, "\033[?2004l")
+}
+
+// enableMouse asks the terminal to report the clicks and the wheel as SGR sequences, if the mouse is enabled in the console.
+// It returns a function to stop the reporting, that can safely be called several times.
+func (c *Console) enableMouse() func() {
+	if !c.mouse {
+		return func() {}
+	}
+
+	fmt.Fprint(c.out, "\033[?1000h\033[?1006h")
+	disabled := false
+	return func() {
+		if !disabled {
+			disabled = true
+			fmt.Fprint(c.out, "\033[?1006l\033[?1000l")
+		}
+	}
 }
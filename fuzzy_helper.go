@@ -0,0 +1,102 @@
+package styledconsole
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch is a choice that matches the filter typed by the user
+type fuzzyMatch struct {
+	index     int
+	score     int
+	positions []int
+}
+
+// fuzzyFilter returns the choices that contain all the characters of the filter in the same order, ignoring the case.
+// The best matches come first: consecutive characters and characters at the beginning of words score higher.
+// With an empty filter, all the choices are returned in their original order.
+func fuzzyFilter(choices []string, filter string) []fuzzyMatch {
+	matches := []fuzzyMatch{}
+	for index, choice := range choices {
+		if score, positions, ok := fuzzyScore(filter, choice); ok {
+			matches = append(matches, fuzzyMatch{index: index, score: score, positions: positions})
+		}
+	}
+
+	if filter != `` {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+	}
+
+	return matches
+}
+
+// fuzzyScore checks whether all the runes of the pattern appear in order in the text, ignoring the case.
+// It returns the score of the match and the positions of the matched runes in the text.
+func fuzzyScore(pattern string, text string) (int, []int, bool) {
+	patternRunes := []rune(strings.ToLower(pattern))
+	textRunes := []rune(text)
+
+	score := 0
+	positions := []int{}
+	next := 0
+	for i := 0; i < len(textRunes) && next < len(patternRunes); i++ {
+		if unicode.ToLower(textRunes[i]) != patternRunes[next] {
+			continue
+		}
+
+		score++
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 5
+		}
+		if i == 0 || isWordSeparator(textRunes[i-1]) {
+			score += 3
+		}
+		positions = append(positions, i)
+		next++
+	}
+
+	if next < len(patternRunes) {
+		return 0, nil, false
+	}
+	if len(positions) > 0 {
+		// The matches that start earlier are preferred
+		score -= positions[0]
+	}
+
+	return score, positions, true
+}
+
+// isWordSeparator returns whether the rune separates two words of a choice
+func isWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("-_./:", r)
+}
+
+// highlightMatches applies the style of the highlighted choice to the runes of the label at the given positions
+func (c *Console) highlightMatches(label string, positions []int) string {
+	if len(positions) == 0 {
+		return label
+	}
+
+	var output strings.Builder
+	runes := []rune(label)
+	next := 0
+	for i := 0; i < len(runes); i++ {
+		if next < len(positions) && positions[next] == i {
+			// Group the consecutive matched runes
+			end := i
+			for next < len(positions) && positions[next] == end && end < len(runes) {
+				end++
+				next++
+			}
+			output.WriteString(c.applyStyle(highlightedChoiceStyle, string(runes[i:end])))
+			i = end - 1
+			continue
+		}
+		output.WriteRune(runes[i])
+	}
+
+	return output.String()
+}
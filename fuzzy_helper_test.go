@@ -0,0 +1,90 @@
+package styledconsole
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFuzzyFilter checks the choices are filtered and sorted by relevance
+func TestFuzzyFilter(t *testing.T) {
+	assert := assert.New(t)
+	choices := []string{"eu-west-1", "us-east-1", "us-east-2", "ap-south-1"}
+
+	indexes := func(matches []fuzzyMatch) []int {
+		result := []int{}
+		for _, match := range matches {
+			result = append(result, match.index)
+		}
+		return result
+	}
+
+	assert.Equal([]int{0, 1, 2, 3}, indexes(fuzzyFilter(choices, ``)))
+	assert.Equal([]int{2}, indexes(fuzzyFilter(choices, "EAST2")))
+	// The word starts and the consecutive characters of us-east-1 score better than eu-west-1
+	assert.Equal([]int{1, 0}, indexes(fuzzyFilter(choices, "ue1")))
+	assert.Empty(fuzzyFilter(choices, "north"))
+
+	score, positions, ok := fuzzyScore("ue1", "us-east-1")
+	assert.True(ok)
+	assert.Equal([]int{0, 3, 8}, positions)
+	assert.Equal(12, score)
+}
+
+// TestHighlightMatches checks the matched characters are styled
+func TestHighlightMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	console := New(strings.NewReader(""), &bytes.Buffer{}, WithColorLevel(styledprinter.ColorLevelNone))
+	assert.Equal("us-east-1", console.highlightMatches("us-east-1", []int{0, 1, 3}))
+
+	console = New(strings.NewReader(""), &bytes.Buffer{}, WithColorLevel(styledprinter.ColorLevelTrueColor))
+	highlighted := console.highlightMatches("us-east-1", []int{0, 1, 3})
+	assert.Equal(console.applyStyle(highlightedChoiceStyle, "us")+"-"+console.applyStyle(highlightedChoiceStyle, "e")+"ast-1", highlighted)
+}
+
+// TestChoiceFilter checks the choices can be filtered by typing, and browsed by page
+func TestChoiceFilter(t *testing.T) {
+	assert := assert.New(t)
+	choices := []string{}
+	for _, region := range []string{"eu-west-1", "eu-west-2", "us-east-1", "us-east-2", "ap-south-1"} {
+		for _, zone := range []string{"a", "b", "c", "d"} {
+			choices = append(choices, region+zone)
+		}
+	}
+
+	answers := map[string]string{
+		"us2c\r":                      "us-east-2c",
+		"uzz\x7f\x7f2\x1b[B\r":        "us-east-2b",
+		"north\r\x15\r":               "eu-west-1a",
+		"\x1b[200~s2b\r\n\x1b[201~\r": "us-east-2b",
+		"\x1b[6~\r":                   "us-east-1c",
+		"\x1b[6~\x1b[B\r":             "us-east-1d",
+		"\x1b[F\r":                    "ap-south-1d",
+		"\x1b[F\x1b[5~\x1b[H\r":       "eu-west-1a",
+		"\x1b[B\x1b[B\x1b[6~\r":       "us-east-2a",
+	}
+	for input, expected := range answers {
+		console := New(strings.NewReader(input), &bytes.Buffer{}, WithTerminal(true), WithSize(40, 20))
+		answer, err := console.Choice("Zone", choices)
+		assert.Nil(err)
+		assert.Equal(expected, answer, "input %q", input)
+	}
+
+	// The filter is displayed next to the label, or a message when nothing matches
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader("zz\x7f\x7fap\r"), out, WithTerminal(true), WithSize(40, 20), WithColorLevel(styledprinter.ColorLevelNone))
+	answer, err := console.Choice("Zone", choices)
+	assert.Nil(err)
+	assert.Equal("ap-south-1a", answer)
+	assert.Contains(out.String(), "Zone: zz")
+	assert.Contains(out.String(), "No choice matches the filter.")
+	assert.Contains(out.String(), "Zone: ap")
+
+	// The bracketed paste mode is only enabled during the prompt
+	assert.True(strings.HasPrefix(out.String(), "\033[?2004h"))
+	assert.Contains(out.String(), "\033[?2004l")
+}
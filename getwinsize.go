@@ -1,15 +1,20 @@
 package styledconsole
 
 import (
-	"os"
-
 	"golang.org/x/term"
 )
 
-// GetWinsize return the size (width, height) of the current terminal window
-func getWinsize() (int, int) {
-	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+// GetWinsize return the size (width, height) of the terminal the console writes to
+func (c *Console) getWinsize() (int, int) {
+	if c.width > 0 && c.height > 0 {
+		return c.width, c.height
+	}
+
+	if c.outFile == nil {
+		return 120, 60
+	}
 
+	width, height, err := term.GetSize(int(c.outFile.Fd()))
 	if err != nil {
 		return 120, 60
 	}
@@ -3,13 +3,14 @@ module github.com/corentindeboisset/styledconsole
 go 1.20
 
 require (
+	github.com/rivo/uniseg v0.4.7
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/sys v0.10.0
 	golang.org/x/term v0.10.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.10.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package styledconsole
+
+import (
+	"syscall"
+)
+
+// sendInterruptSignal sends SIGINT to the program, as the terminal does when Ctrl-C is pressed outside of raw mode
+func sendInterruptSignal() {
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+}
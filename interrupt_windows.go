@@ -0,0 +1,12 @@
+//go:build windows
+
+package styledconsole
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// sendInterruptSignal sends a Ctrl-C event to the processes attached to the console, which Go reports as os.Interrupt
+func sendInterruptSignal() {
+	_ = windows.GenerateConsoleCtrlEvent(windows.CTRL_C_EVENT, 0)
+}
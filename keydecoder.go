@@ -0,0 +1,637 @@
+package styledconsole
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// pasteStart and pasteEnd surround the pasted text in bracketed paste mode
+	pasteStart = []byte("\033[200~")
+	pasteEnd   = []byte("\033[201~")
+)
+
+// defaultEscapeTimeout is how long the decoder waits for the rest of an escape sequence before reporting a lone Esc
+const defaultEscapeTimeout = 50 * time.Millisecond
+
+// Key identifies a key pressed by the user
+type Key int
+
+// The keys recognized by a KeyDecoder. The characters, including the ones typed with Ctrl or Alt, are reported as KeyRune.
+const (
+	KeyUnknown Key = iota
+	KeyRune
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyInsert
+	KeyDelete
+	KeyPageUp
+	KeyPageDown
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	// KeyPaste is some text pasted in a terminal in bracketed paste mode, see KeyEvent.Text
+	KeyPaste
+	// KeyMouse and KeyMouseRelease are a mouse button pressed and released while the SGR mouse mode is enabled,
+	// see KeyEvent.Button, KeyEvent.X and KeyEvent.Y
+	KeyMouse
+	KeyMouseRelease
+	// KeyCursorPosition is the answer of the terminal to KeyDecoder.RequestCursorPosition(), see KeyEvent.X and KeyEvent.Y
+	KeyCursorPosition
+)
+
+var keyNames = []string{
+	"Unknown", "Rune", "Enter", "Tab", "Backspace", "Esc", "Up", "Down", "Right", "Left", "Home", "End", "Insert", "Delete",
+	"PageUp", "PageDown", "F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12", "Paste",
+	"Mouse", "MouseRelease", "CursorPosition",
+}
+
+// String returns the name of the key, like "PageUp"
+func (k Key) String() string {
+	if k < 0 || int(k) >= len(keyNames) {
+		return "Key(" + strconv.Itoa(int(k)) + ")"
+	}
+
+	return keyNames[k]
+}
+
+// MouseButton identifies the button of a mouse event
+type MouseButton int
+
+// The mouse buttons, the wheel being reported as buttons that are only pressed
+const (
+	MouseLeft MouseButton = iota
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// Modifier is a combination of the modifier keys held while a key is pressed
+type Modifier int
+
+// The modifier keys, their values match the ones sent by xterm-compatible terminals
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+// KeyEvent is a key pressed by the user, with its modifiers.
+// Ctrl-A is reported as the rune 'a' with ModCtrl, and Shift-Tab as KeyTab with ModShift.
+type KeyEvent struct {
+	Key       Key
+	Rune      rune
+	Modifiers Modifier
+	// Text is the pasted text of a KeyPaste event
+	Text string
+	// Button is the button of a mouse event
+	Button MouseButton
+	// X and Y are the column and the row of a mouse event or of the cursor, starting from 1 at the top left of the screen
+	X int
+	Y int
+}
+
+// String describes the key with its modifiers, like "Ctrl+Alt+Up", "Ctrl+c" or `Paste "text"`
+func (e KeyEvent) String() string {
+	var output strings.Builder
+	for _, modifier := range []struct {
+		flag Modifier
+		name string
+	}{{ModCtrl, "Ctrl+"}, {ModAlt, "Alt+"}, {ModShift, "Shift+"}} {
+		if e.Modifiers&modifier.flag != 0 {
+			output.WriteString(modifier.name)
+		}
+	}
+
+	if e.Key == KeyRune {
+		output.WriteRune(e.Rune)
+	} else if e.Key == KeyPaste {
+		output.WriteString("Paste " + strconv.Quote(e.Text))
+	} else if e.Key == KeyMouse || e.Key == KeyMouseRelease || e.Key == KeyCursorPosition {
+		fmt.Fprintf(&output, "%s(%d,%d)", e.Key, e.X, e.Y)
+	} else {
+		output.WriteString(e.Key.String())
+	}
+
+	return output.String()
+}
+
+// isCtrl returns whether the event is the given letter typed with Ctrl only
+func (e KeyEvent) isCtrl(letter rune) bool {
+	return e.Key == KeyRune && e.Modifiers == ModCtrl && e.Rune == letter
+}
+
+// pastedRunes returns the printable characters of a pasted text, with its line breaks replaced by the given text
+func (e KeyEvent) pastedRunes(lineBreak string) []rune {
+	text := strings.NewReplacer("\r\n", lineBreak, "\r", lineBreak, "\n", lineBreak, "\t", " ").Replace(e.Text)
+
+	runes := []rune{}
+	for _, r := range text {
+		if unicode.IsPrint(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// isCharacter returns whether the event is a character to insert in a text, typed without Ctrl nor Alt
+func (e KeyEvent) isCharacter() bool {
+	return e.Key == KeyRune && e.Modifiers&^ModShift == 0
+}
+
+// KeyDecoder reads the keys pressed by the user from the input of a terminal in raw mode.
+// It understands the escape sequences of xterm-compatible terminals and the UTF-8 characters.
+// The bytes that are read ahead are kept for the next calls, so a single decoder should be used for a given input.
+// A KeyDecoder is not safe for concurrent use.
+type KeyDecoder struct {
+	reader        io.Reader
+	buffer        []byte
+	escapeTimeout time.Duration
+
+	// Reads happen in a goroutine, so that the decoder can stop waiting for the end of an escape sequence
+	reading bool
+	results chan readResult
+	err     error
+	waiter  readWaiter
+
+	// pendingPositions is the amount of cursor positions requested to the terminal that were not received yet
+	pendingPositions int
+}
+
+// readResult is what a read of the underlying reader returned
+type readResult struct {
+	data      []byte
+	err       error
+	cancelled bool
+}
+
+// readWaiter waits for the underlying reader to have bytes to read, so that a pending read can be cancelled
+type readWaiter interface {
+	// wait blocks until the reader can be read without blocking, and returns false if the wait was cancelled
+	wait() bool
+	// cancel stops the current wait, or the next one if the reader is already being read
+	cancel()
+	// reset forgets a cancellation that was not received by a wait
+	reset()
+}
+
+// NewKeyDecoder instanciates a decoder that reads the keys from the given reader.
+// When the reader is a file, like os.Stdin, the decoder stops reading it as soon as a read is cancelled, and the bytes
+// typed afterwards are left to the next readers. Otherwise, and on Windows, the cancelled read stays pending
+// and its bytes are kept for the next calls of the decoder.
+func NewKeyDecoder(reader io.Reader) *KeyDecoder {
+	return &KeyDecoder{
+		reader:        reader,
+		escapeTimeout: defaultEscapeTimeout,
+		results:       make(chan readResult, 1),
+		waiter:        newReadWaiter(reader),
+	}
+}
+
+// SetEscapeTimeout changes how long the decoder waits for the rest of an escape sequence, before reporting that Esc was pressed.
+// The default timeout is 50ms, a longer one may be needed through slow connections.
+func (d *KeyDecoder) SetEscapeTimeout(timeout time.Duration) {
+	d.escapeTimeout = timeout
+}
+
+// ReadKey waits for the next key pressed by the user. The escape sequences that are not recognized are skipped.
+// It returns io.EOF once the input is closed.
+func (d *KeyDecoder) ReadKey() (KeyEvent, error) {
+	return d.ReadKeyContext(context.Background())
+}
+
+// ReadKeyContext is the same as ReadKey() but it stops waiting and returns ctx.Err() once the context is done.
+// The bytes that arrive afterwards are kept for the next calls.
+func (d *KeyDecoder) ReadKeyContext(ctx context.Context) (KeyEvent, error) {
+	for {
+		if len(d.buffer) == 0 {
+			if err := d.fill(ctx, 0); err != nil {
+				return KeyEvent{}, err
+			}
+			continue
+		}
+
+		event, length := decodeKey(d.buffer)
+		if length == 0 {
+			// The sequence is incomplete, the rest may still be coming. A pasted text can be long, its end is always waited for.
+			timeout := d.escapeTimeout
+			if bytes.HasPrefix(d.buffer, pasteStart) {
+				timeout = 0
+			}
+			err := d.fill(ctx, timeout)
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				return KeyEvent{}, ctx.Err()
+			}
+			if err != errReadTimeout {
+				// The read error is reported by the next call, once the incomplete key is returned
+				d.err = err
+			}
+			event, length = decodeIncompleteKey(d.buffer)
+		}
+
+		d.buffer = d.buffer[length:]
+		if event.Key == KeyCursorPosition {
+			event = d.checkCursorPosition(event)
+		}
+		if event.Key != KeyUnknown {
+			return event, nil
+		}
+	}
+}
+
+// RequestCursorPosition asks the terminal where the cursor is, by writing a request to its output.
+// The answer is reported by ReadKey() as a KeyCursorPosition event.
+func (d *KeyDecoder) RequestCursorPosition(output io.Writer) error {
+	if _, err := fmt.Fprint(output, "\033[6n"); err != nil {
+		return err
+	}
+
+	d.pendingPositions++
+	return nil
+}
+
+// checkCursorPosition returns the cursor position if one was requested. Otherwise the sequence is F3 with modifiers,
+// which xterm sends as "<esc>[1;<modifiers>R".
+func (d *KeyDecoder) checkCursorPosition(event KeyEvent) KeyEvent {
+	if d.pendingPositions > 0 {
+		d.pendingPositions--
+		return event
+	}
+	if event.Y == 1 {
+		return KeyEvent{Key: KeyF3, Modifiers: modifiersFromParameter(strconv.Itoa(event.X))}
+	}
+
+	return KeyEvent{}
+}
+
+// errReadTimeout is returned by fill when no byte came in time
+var errReadTimeout = errors.New("no input was received in time")
+
+// fill appends the next bytes of the reader to the buffer. With a positive timeout, it returns errReadTimeout if no byte came in time.
+// It returns ctx.Err() once the context is done, after cancelling the pending read if the reader allows it.
+// Otherwise the pending read is received by the next call.
+func (d *KeyDecoder) fill(ctx context.Context, timeout time.Duration) error {
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	for {
+		if d.err != nil {
+			// The error is only reported once, as the reader may still have more to read, like a terminal after Ctrl-D
+			err := d.err
+			d.err = nil
+			return err
+		}
+
+		if !d.reading {
+			d.reading = true
+			go func() {
+				if d.waiter != nil && !d.waiter.wait() {
+					d.results <- readResult{cancelled: true}
+					return
+				}
+
+				data := make([]byte, 256)
+				n, err := d.reader.Read(data)
+				d.results <- readResult{data: data[:n], err: err}
+			}()
+		}
+
+		select {
+		case result := <-d.results:
+			if d.receive(result) {
+				return nil
+			}
+		case <-timer:
+			if d.cancelRead() {
+				return nil
+			}
+			return errReadTimeout
+		case <-ctx.Done():
+			d.cancelRead()
+			return ctx.Err()
+		}
+	}
+}
+
+// receive adds the result of a read to the buffer, and returns whether some bytes were read
+func (d *KeyDecoder) receive(result readResult) bool {
+	d.reading = false
+	d.buffer = append(d.buffer, result.data...)
+	// The error is reported once the bytes that came with it are decoded
+	d.err = result.err
+
+	return len(result.data) > 0
+}
+
+// cancelRead stops the pending read if the reader allows it, so that no goroutine keeps reading the input once the
+// prompt is over. It returns whether some bytes were read in the meantime.
+func (d *KeyDecoder) cancelRead() bool {
+	if d.waiter == nil || !d.reading {
+		return false
+	}
+
+	d.waiter.cancel()
+	result := <-d.results
+	d.waiter.reset()
+
+	return d.receive(result)
+}
+
+// decodeKey decodes the key at the beginning of the bytes and returns its length, or 0 if the bytes are an incomplete sequence
+func decodeKey(input []byte) (KeyEvent, int) {
+	if input[0] != '\033' {
+		return decodeCharacter(input)
+	}
+	if len(input) == 1 {
+		// Either Esc or the beginning of a sequence
+		return KeyEvent{}, 0
+	}
+	if bytes.HasPrefix(input, pasteStart) {
+		end := bytes.Index(input, pasteEnd)
+		if end < 0 {
+			return KeyEvent{}, 0
+		}
+		return KeyEvent{Key: KeyPaste, Text: string(input[len(pasteStart):end])}, end + len(pasteEnd)
+	}
+
+	switch input[1] {
+	case '[':
+		return decodeCSISequence(input)
+	case 'O':
+		return decodeSS3Sequence(input)
+	case '\033':
+		if len(input) == 2 {
+			return KeyEvent{}, 0
+		}
+		if input[2] != '[' && input[2] != 'O' {
+			return KeyEvent{Key: KeyEscape}, 1
+		}
+	}
+
+	// The Alt modifier is sent as an escape before the key
+	event, length := decodeKey(input[1:])
+	if length == 0 {
+		return KeyEvent{}, 0
+	}
+	event.Modifiers |= ModAlt
+	return event, length + 1
+}
+
+// decodeIncompleteKey decodes the beginning of an incomplete sequence once it is known that the rest will not come
+func decodeIncompleteKey(input []byte) (KeyEvent, int) {
+	if bytes.HasPrefix(input, pasteStart) {
+		// The input was closed during the paste
+		return KeyEvent{Key: KeyPaste, Text: string(input[len(pasteStart):])}, len(input)
+	}
+	if input[0] == '\033' {
+		return KeyEvent{Key: KeyEscape}, 1
+	}
+
+	return KeyEvent{Key: KeyRune, Rune: utf8.RuneError}, 1
+}
+
+// decodeCharacter decodes a UTF-8 character, the control characters being reported as letters typed with Ctrl
+func decodeCharacter(input []byte) (KeyEvent, int) {
+	if !utf8.FullRune(input) {
+		return KeyEvent{}, 0
+	}
+	r, length := utf8.DecodeRune(input)
+
+	switch {
+	case r == '\r' || r == '\n':
+		return KeyEvent{Key: KeyEnter}, length
+	case r == '\t':
+		return KeyEvent{Key: KeyTab}, length
+	case r == 127 || r == 8:
+		return KeyEvent{Key: KeyBackspace}, length
+	case r == '\033':
+		return KeyEvent{Key: KeyEscape}, length
+	case r == 0:
+		return KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl}, length
+	case r < 27:
+		return KeyEvent{Key: KeyRune, Rune: 'a' + r - 1, Modifiers: ModCtrl}, length
+	case r < 32:
+		// Ctrl-\, Ctrl-], Ctrl-^ and Ctrl-_
+		return KeyEvent{Key: KeyRune, Rune: r + 64, Modifiers: ModCtrl}, length
+	}
+
+	return KeyEvent{Key: KeyRune, Rune: r}, length
+}
+
+// decodeCSISequence decodes a sequence starting with "<esc>[", made of parameters, intermediate bytes and a final character
+func decodeCSISequence(input []byte) (KeyEvent, int) {
+	if len(input) < 3 {
+		return KeyEvent{}, 0
+	}
+	if input[2] == '[' {
+		// The Linux console sends F1 to F5 as "<esc>[[A" to "<esc>[[E"
+		if len(input) < 4 {
+			return KeyEvent{}, 0
+		}
+		if input[3] >= 'A' && input[3] <= 'E' {
+			return KeyEvent{Key: KeyF1 + Key(input[3]-'A')}, 4
+		}
+		return KeyEvent{}, 4
+	}
+
+	end := 2
+	for end < len(input) && input[end] >= 0x30 && input[end] <= 0x3f {
+		end++
+	}
+	for end < len(input) && input[end] >= 0x20 && input[end] <= 0x2f {
+		end++
+	}
+	if end >= len(input) {
+		return KeyEvent{}, 0
+	}
+	if input[end] < 0x40 || input[end] > 0x7e {
+		// Malformed sequence, it is skipped up to the unexpected byte
+		return KeyEvent{}, end
+	}
+
+	parameters := strings.Split(string(input[2:end]), ";")
+	if input[2] == '<' && (input[end] == 'M' || input[end] == 'm') {
+		return decodeMouseEvent(parameters, input[end] == 'm'), end + 1
+	}
+	if input[end] == 'R' && len(parameters) == 2 {
+		// Either the position of the cursor or F3 with modifiers, see KeyDecoder.checkCursorPosition()
+		row, rowErr := strconv.Atoi(parameters[0])
+		column, columnErr := strconv.Atoi(parameters[1])
+		if rowErr == nil && columnErr == nil {
+			return KeyEvent{Key: KeyCursorPosition, X: column, Y: row}, end + 1
+		}
+	}
+
+	event := KeyEvent{Key: keyFromFinalCharacter(input[end])}
+	if input[end] == '~' {
+		event.Key = keyFromTildeParameter(parameters[0])
+	}
+	if input[end] == 'Z' {
+		event = KeyEvent{Key: KeyTab, Modifiers: ModShift}
+	}
+
+	if len(parameters) > 1 {
+		event.Modifiers |= modifiersFromParameter(parameters[1])
+	}
+
+	return event, end + 1
+}
+
+// decodeMouseEvent decodes the parameters of a SGR mouse sequence, "<esc>[<button;x;y" followed by M when the button is pressed,
+// or m when it is released
+func decodeMouseEvent(parameters []string, released bool) KeyEvent {
+	if len(parameters) != 3 {
+		return KeyEvent{}
+	}
+	code, codeErr := strconv.Atoi(strings.TrimPrefix(parameters[0], "<"))
+	x, xErr := strconv.Atoi(parameters[1])
+	y, yErr := strconv.Atoi(parameters[2])
+	if codeErr != nil || xErr != nil || yErr != nil || code&32 != 0 {
+		// The motion events are not reported
+		return KeyEvent{}
+	}
+
+	event := KeyEvent{Key: KeyMouse, X: x, Y: y}
+	if released {
+		event.Key = KeyMouseRelease
+	}
+	if code&4 != 0 {
+		event.Modifiers |= ModShift
+	}
+	if code&8 != 0 {
+		event.Modifiers |= ModAlt
+	}
+	if code&16 != 0 {
+		event.Modifiers |= ModCtrl
+	}
+
+	switch code &^ (4 | 8 | 16) {
+	case 0:
+		event.Button = MouseLeft
+	case 1:
+		event.Button = MouseMiddle
+	case 2:
+		event.Button = MouseRight
+	case 64:
+		event.Button = MouseWheelUp
+	case 65:
+		event.Button = MouseWheelDown
+	default:
+		return KeyEvent{}
+	}
+
+	return event
+}
+
+// decodeSS3Sequence decodes a sequence starting with "<esc>O", sent for some keys in the application mode of the terminal
+func decodeSS3Sequence(input []byte) (KeyEvent, int) {
+	end := 2
+	for end < len(input) && input[end] >= '0' && input[end] <= '9' {
+		end++
+	}
+	if end >= len(input) {
+		return KeyEvent{}, 0
+	}
+
+	event := KeyEvent{Key: keyFromFinalCharacter(input[end])}
+	if end > 2 {
+		// Some terminals send the modifiers between the "O" and the final character
+		event.Modifiers = modifiersFromParameter(string(input[2:end]))
+	}
+
+	return event, end + 1
+}
+
+// keyFromFinalCharacter returns the key identified by the final character of a CSI or SS3 sequence
+func keyFromFinalCharacter(final byte) Key {
+	switch final {
+	case 'A':
+		return KeyUp
+	case 'B':
+		return KeyDown
+	case 'C':
+		return KeyRight
+	case 'D':
+		return KeyLeft
+	case 'H':
+		return KeyHome
+	case 'F':
+		return KeyEnd
+	case 'P', 'Q', 'R', 'S':
+		return KeyF1 + Key(final-'P')
+	}
+
+	return KeyUnknown
+}
+
+// keyFromTildeParameter returns the key identified by the number of a sequence like "<esc>[5~"
+func keyFromTildeParameter(parameter string) Key {
+	switch parameter {
+	case "1", "7":
+		return KeyHome
+	case "2":
+		return KeyInsert
+	case "3":
+		return KeyDelete
+	case "4", "8":
+		return KeyEnd
+	case "5":
+		return KeyPageUp
+	case "6":
+		return KeyPageDown
+	case "11", "12", "13", "14", "15":
+		number, _ := strconv.Atoi(parameter)
+		return KeyF1 + Key(number-11)
+	case "17", "18", "19", "20", "21":
+		number, _ := strconv.Atoi(parameter)
+		return KeyF6 + Key(number-17)
+	case "23", "24":
+		number, _ := strconv.Atoi(parameter)
+		return KeyF11 + Key(number-23)
+	}
+
+	return KeyUnknown
+}
+
+// modifiersFromParameter converts the modifier parameter of a sequence, which is 1 plus the flags of the modifiers
+func modifiersFromParameter(parameter string) Modifier {
+	value, err := strconv.Atoi(parameter)
+	if err != nil || value < 1 {
+		return 0
+	}
+
+	return Modifier(value-1) & (ModShift | ModAlt | ModCtrl)
+}
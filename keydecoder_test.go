@@ -0,0 +1,219 @@
+package styledconsole
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecodeKey checks the characters and the escape sequences sent by the terminals are recognized
+func TestDecodeKey(t *testing.T) {
+	assert := assert.New(t)
+
+	sequences := map[string]KeyEvent{
+		"a":                      {Key: KeyRune, Rune: 'a'},
+		"é":                      {Key: KeyRune, Rune: 'é'},
+		"\r":                     {Key: KeyEnter},
+		"\t":                     {Key: KeyTab},
+		"\x7f":                   {Key: KeyBackspace},
+		"\x03":                   {Key: KeyRune, Rune: 'c', Modifiers: ModCtrl},
+		"\x1f":                   {Key: KeyRune, Rune: '_', Modifiers: ModCtrl},
+		"\x1b[A":                 {Key: KeyUp},
+		"\x1bOD":                 {Key: KeyLeft},
+		"\x1bOH":                 {Key: KeyHome},
+		"\x1b[4~":                {Key: KeyEnd},
+		"\x1b[2~":                {Key: KeyInsert},
+		"\x1b[3~":                {Key: KeyDelete},
+		"\x1b[5~":                {Key: KeyPageUp},
+		"\x1b[6~":                {Key: KeyPageDown},
+		"\x1bOP":                 {Key: KeyF1},
+		"\x1b[[E":                {Key: KeyF5},
+		"\x1b[15~":               {Key: KeyF5},
+		"\x1b[24~":               {Key: KeyF12},
+		"\x1b[Z":                 {Key: KeyTab, Modifiers: ModShift},
+		"\x1b[1;5C":              {Key: KeyRight, Modifiers: ModCtrl},
+		"\x1b[1;4A":              {Key: KeyUp, Modifiers: ModShift | ModAlt},
+		"\x1b[15;2~":             {Key: KeyF5, Modifiers: ModShift},
+		"\x1bO5P":                {Key: KeyF1, Modifiers: ModCtrl},
+		"\x1bf":                  {Key: KeyRune, Rune: 'f', Modifiers: ModAlt},
+		"\x1b\x7f":               {Key: KeyBackspace, Modifiers: ModAlt},
+		"\x1b\x1b[B":             {Key: KeyDown, Modifiers: ModAlt},
+		"\x1b\x01":               {Key: KeyRune, Rune: 'a', Modifiers: ModCtrl | ModAlt},
+		"\x1b\x1bx":              {Key: KeyEscape},
+		"\x1b[200~a\nb\x1b[201~": {Key: KeyPaste, Text: "a\nb"},
+		"\x1b[<0;12;5M":          {Key: KeyMouse, Button: MouseLeft, X: 12, Y: 5},
+		"\x1b[<18;3;4m":          {Key: KeyMouseRelease, Button: MouseRight, X: 3, Y: 4, Modifiers: ModCtrl},
+		"\x1b[<65;1;1M":          {Key: KeyMouse, Button: MouseWheelDown, X: 1, Y: 1},
+		"\x1b[<32;1;1M":          {},
+		"\x1b[12;40R":            {Key: KeyCursorPosition, X: 40, Y: 12},
+		"\x1b[99;1 q":            {},
+	}
+	for sequence, expected := range sequences {
+		event, length := decodeKey([]byte(sequence + "next"))
+		assert.Equal(expected, event, "sequence %q", sequence)
+		if expected.Key == KeyEscape {
+			assert.Equal(1, length, "sequence %q", sequence)
+		} else {
+			assert.Equal(len(sequence), length, "sequence %q", sequence)
+		}
+	}
+
+	// Incomplete sequences need more bytes
+	for _, sequence := range []string{"\x1b", "\x1b[", "\x1b[1;", "\x1bO", "\x1b\x1b", "\x1b\x1b[", "\xc3", "\x1b[200~text"} {
+		_, length := decodeKey([]byte(sequence))
+		assert.Equal(0, length, "sequence %q", sequence)
+	}
+}
+
+// TestKeyEventString checks the keys are described with their modifiers
+func TestKeyEventString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Ctrl+Alt+Up", KeyEvent{Key: KeyUp, Modifiers: ModCtrl | ModAlt}.String())
+	assert.Equal("Ctrl+c", KeyEvent{Key: KeyRune, Rune: 'c', Modifiers: ModCtrl}.String())
+	assert.Equal("Shift+Tab", KeyEvent{Key: KeyTab, Modifiers: ModShift}.String())
+	assert.Equal("F12", KeyF12.String())
+	assert.Equal(`Paste "a\nb"`, KeyEvent{Key: KeyPaste, Text: "a\nb"}.String())
+	assert.Equal("Shift+Mouse(3,4)", KeyEvent{Key: KeyMouse, X: 3, Y: 4, Modifiers: ModShift}.String())
+}
+
+// TestKeyDecoder checks the keys are read one after the other, and unknown sequences are skipped
+func TestKeyDecoder(t *testing.T) {
+	assert := assert.New(t)
+
+	decoder := NewKeyDecoder(strings.NewReader("a\x1b[99~\x1b[Bé\x1b"))
+	expected := []KeyEvent{{Key: KeyRune, Rune: 'a'}, {Key: KeyDown}, {Key: KeyRune, Rune: 'é'}, {Key: KeyEscape}}
+	for _, expectedEvent := range expected {
+		event, err := decoder.ReadKey()
+		assert.Nil(err)
+		assert.Equal(expectedEvent, event)
+	}
+
+	_, err := decoder.ReadKey()
+	assert.Equal(io.EOF, err)
+}
+
+// TestKeyDecoderTimeout checks a lone Esc is reported after a timeout, while the sequences split across reads are waited for
+func TestKeyDecoderTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, writer := io.Pipe()
+	decoder := NewKeyDecoder(reader)
+	decoder.SetEscapeTimeout(200 * time.Millisecond)
+
+	go func() {
+		_, _ = writer.Write([]byte("\x1b["))
+		time.Sleep(20 * time.Millisecond)
+		_, _ = writer.Write([]byte("A\xc3"))
+		time.Sleep(20 * time.Millisecond)
+		_, _ = writer.Write([]byte("\xa9\x1b"))
+		time.Sleep(400 * time.Millisecond)
+		_, _ = writer.Write([]byte("\x1b[B"))
+		_ = writer.Close()
+	}()
+
+	expected := []KeyEvent{{Key: KeyUp}, {Key: KeyRune, Rune: 'é'}, {Key: KeyEscape}, {Key: KeyDown}}
+	for _, expectedEvent := range expected {
+		event, err := decoder.ReadKey()
+		assert.Nil(err)
+		assert.Equal(expectedEvent, event)
+	}
+
+	_, err := decoder.ReadKey()
+	assert.Equal(io.EOF, err)
+}
+
+// TestKeyDecoderPaste checks a pasted text is waited for without timeout, and reported as a single event
+func TestKeyDecoderPaste(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, writer := io.Pipe()
+	decoder := NewKeyDecoder(reader)
+	decoder.SetEscapeTimeout(10 * time.Millisecond)
+
+	go func() {
+		_, _ = writer.Write([]byte("\x1b[200~first line\r\n"))
+		time.Sleep(50 * time.Millisecond)
+		_, _ = writer.Write([]byte("second line\x1b[201~\r\x1b[200~unfinished"))
+		_ = writer.Close()
+	}()
+
+	expected := []KeyEvent{
+		{Key: KeyPaste, Text: "first line\r\nsecond line"},
+		{Key: KeyEnter},
+		{Key: KeyPaste, Text: "unfinished"},
+	}
+	for _, expectedEvent := range expected {
+		event, err := decoder.ReadKey()
+		assert.Nil(err)
+		assert.Equal(expectedEvent, event)
+	}
+	assert.Equal([]rune("first line second line"), expected[0].pastedRunes(" "))
+}
+
+// TestKeyDecoderCursorPosition checks the positions of the cursor are only reported when they were requested
+func TestKeyDecoderCursorPosition(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &strings.Builder{}
+	decoder := NewKeyDecoder(strings.NewReader("\x1b[1;5R\x1b[1;5R\x1b[7;1R"))
+	assert.Nil(decoder.RequestCursorPosition(out))
+	assert.Equal("\x1b[6n", out.String())
+
+	expected := []KeyEvent{
+		{Key: KeyCursorPosition, X: 5, Y: 1},
+		{Key: KeyF3, Modifiers: ModCtrl},
+	}
+	for _, expectedEvent := range expected {
+		event, err := decoder.ReadKey()
+		assert.Nil(err)
+		assert.Equal(expectedEvent, event)
+	}
+
+	// An unexpected position that cannot be F3 is skipped
+	_, err := decoder.ReadKey()
+	assert.Equal(io.EOF, err)
+}
+
+// scriptedReader returns the given results one after the other, then io.EOF
+type scriptedReader struct {
+	results []readResult
+}
+
+func (r *scriptedReader) Read(p []byte) (int, error) {
+	if len(r.results) == 0 {
+		return 0, io.EOF
+	}
+
+	result := r.results[0]
+	r.results = r.results[1:]
+	return copy(p, result.data), result.err
+}
+
+// TestKeyDecoderError checks a read error is reported once, and the input that follows it is still read
+func TestKeyDecoderError(t *testing.T) {
+	assert := assert.New(t)
+
+	decoder := NewKeyDecoder(&scriptedReader{results: []readResult{{data: []byte("a"), err: io.EOF}, {data: []byte("b")}}})
+	event, err := decoder.ReadKey()
+	assert.Nil(err)
+	assert.Equal(KeyEvent{Key: KeyRune, Rune: 'a'}, event)
+	_, err = decoder.ReadKey()
+	assert.Equal(io.EOF, err)
+	event, err = decoder.ReadKey()
+	assert.Nil(err)
+	assert.Equal(KeyEvent{Key: KeyRune, Rune: 'b'}, event)
+
+	// A Ctrl-D typed in a prompt does not end the input for the next prompts
+	console := New(&scriptedReader{results: []readResult{{err: io.EOF}, {data: []byte("my answer\n")}}}, &bytes.Buffer{}, WithTerminal(true))
+	confirmed, err := console.ConfirmWithDefault("Confirm", true)
+	assert.Nil(err)
+	assert.True(confirmed)
+	answer, err := console.Ask("Question", nil)
+	assert.Nil(err)
+	assert.Equal("my answer", answer)
+}
@@ -0,0 +1,69 @@
+//go:build !windows
+
+package styledconsole
+
+import (
+	"io"
+
+	"golang.org/x/sys/unix"
+)
+
+// pollWaiter waits for the file descriptor of the input to be readable with poll(), along with a pipe that is written
+// to cancel the wait. The input is only read once some bytes are available, so that a cancelled read consumes nothing.
+type pollWaiter struct {
+	fd   int
+	wake [2]int
+}
+
+// newReadWaiter returns a waiter for the readers that are backed by a file, or nil if the reads cannot be cancelled
+func newReadWaiter(reader io.Reader) readWaiter {
+	file, ok := reader.(fileDescriptor)
+	if !ok {
+		return nil
+	}
+
+	wake := make([]int, 2)
+	if err := unix.Pipe(wake); err != nil {
+		return nil
+	}
+	for _, fd := range wake {
+		unix.CloseOnExec(fd)
+		_ = unix.SetNonblock(fd, true)
+	}
+
+	return &pollWaiter{fd: int(file.Fd()), wake: [2]int{wake[0], wake[1]}}
+}
+
+func (w *pollWaiter) wait() bool {
+	fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}, {Fd: int32(w.wake[0]), Events: unix.POLLIN}}
+	for {
+		_, err := unix.Poll(fds, -1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			// The input is read without waiting, the read cannot be cancelled anymore
+			return true
+		}
+
+		if fds[1].Revents != 0 {
+			return false
+		}
+		if fds[0].Revents != 0 {
+			return true
+		}
+	}
+}
+
+func (w *pollWaiter) cancel() {
+	_, _ = unix.Write(w.wake[1], []byte{0})
+}
+
+func (w *pollWaiter) reset() {
+	buffer := make([]byte, 16)
+	for {
+		if n, err := unix.Read(w.wake[0], buffer); n <= 0 || err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package styledconsole
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKeyDecoderCancelledRead checks the input typed after a prompt timed out is left to the next readers
+func TestKeyDecoderCancelledRead(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, writer, err := os.Pipe()
+	assert.Nil(err)
+	defer reader.Close()
+	defer writer.Close()
+
+	console := New(reader, &bytes.Buffer{}, WithTerminal(true), WithAnswerTimeout(20*time.Millisecond))
+	_, err = console.Ask("Question", nil)
+	assert.Equal(ErrTimeout, err)
+
+	_, err = writer.WriteString("for the next reader\n")
+	assert.Nil(err)
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	assert.Nil(err)
+	assert.Equal("for the next reader\n", line)
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package styledconsole
+
+import (
+	"io"
+)
+
+// newReadWaiter returns nil on Windows, where the reads of the console cannot be cancelled
+func newReadWaiter(reader io.Reader) readWaiter {
+	return nil
+}
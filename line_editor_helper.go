@@ -0,0 +1,566 @@
+package styledconsole
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"github.com/rivo/uniseg"
+)
+
+// lineEditor edits a line of text in a terminal in raw mode, with the usual shortcuts of a shell
+type lineEditor struct {
+	console *Console
+	prompt  string
+
+	line   []rune
+	cursor int
+	killed []rune
+
+	// cursorRow and endRow are the rows of the cursor and of the end of the line, from the row of the prompt.
+	// The line wraps on several rows once it is wider than the terminal.
+	cursorRow int
+	endRow    int
+
+	history      []string
+	historyIndex int
+	draft        []rune
+
+	completer  func(string) []string
+	completion *completion
+	// candidates are the results of the completer for candidatesLine, it is only called again once the line changes
+	candidates      []string
+	candidatesLine  string
+	candidatesFound bool
+	suggestion      string
+	suggestionShown bool
+}
+
+// completion holds the candidates that are cycled through with the Tab key
+type completion struct {
+	candidates []string
+	index      int
+}
+
+// WithHistoryFile keeps the answers of the prompts in a file, so that they can be recalled with the up and down arrows
+// across several runs of the program. Without this option, the history is only kept in memory.
+func WithHistoryFile(path string) Option {
+	return func(c *Console) {
+		c.historyFile = path
+	}
+}
+
+// readLine reads a line typed by the user after the given prompt, which must already be printed.
+// The completer is optional, and gives the candidates to complete the line with.
+func (c *Console) readLine(ctx context.Context, prompt string, completer func(string) []string) (string, error) {
+	editor := &lineEditor{console: c, prompt: prompt, history: c.loadHistory(), completer: completer}
+	editor.historyIndex = len(editor.history)
+
+	return c.editLine(ctx, editor)
+}
+
+// readShortAnswer reads an answer typed after the given prompt, which must already be printed.
+// Unlike readLine, the answer is not kept in the history, which is meant for the answers of Ask().
+func (c *Console) readShortAnswer(ctx context.Context, prompt string) (string, error) {
+	return c.editLine(ctx, &lineEditor{console: c, prompt: prompt})
+}
+
+// editLine runs the line editor with the input of the console in raw mode
+func (c *Console) editLine(ctx context.Context, editor *lineEditor) (string, error) {
+	restoreTerminal, err := c.makeRaw()
+	if err != nil {
+		return "", fmt.Errorf("there was an error switching terminal to raw mode: %w", err)
+	}
+	defer restoreTerminal()
+
+	return editor.run(ctx)
+}
+
+// run handles the keys typed by the user until the line is validated
+func (e *lineEditor) run(ctx context.Context) (string, error) {
+	// The prompt is already printed, and may have wrapped
+	width := e.terminalWidth()
+	if promptWidth := displayedWidth(e.prompt); promptWidth > 0 {
+		e.cursorRow = (promptWidth - 1) / width
+		e.endRow = e.cursorRow
+	}
+
+	for {
+		key, err := e.console.keys.ReadKeyContext(ctx)
+		if err == io.EOF {
+			e.finish()
+			return string(e.line), io.EOF
+		}
+		if err != nil {
+			e.finish()
+			return "", err
+		}
+
+		if key.Key != KeyTab {
+			e.completion = nil
+		}
+
+		wordJump := key.Modifiers&(ModCtrl|ModAlt) != 0
+		switch key.Key {
+		case KeyEnter:
+			e.acceptSuggestion()
+			e.finish()
+			return string(e.line), nil
+		case KeyTab:
+			if key.Modifiers&ModShift != 0 {
+				e.complete(-1)
+			} else {
+				e.complete(1)
+			}
+		case KeyBackspace:
+			e.deleteRange(e.previousGrapheme(), e.cursor, false)
+		case KeyLeft:
+			if wordJump {
+				e.moveTo(e.previousWordStart())
+			} else {
+				e.moveTo(e.previousGrapheme())
+			}
+		case KeyRight:
+			if wordJump {
+				e.moveTo(e.nextWordEnd())
+			} else if !e.acceptSuggestion() {
+				e.moveTo(e.nextGrapheme())
+			}
+		case KeyUp:
+			e.recallHistory(e.historyIndex - 1)
+		case KeyDown:
+			e.recallHistory(e.historyIndex + 1)
+		case KeyHome:
+			e.moveTo(0)
+		case KeyEnd:
+			if !e.acceptSuggestion() {
+				e.moveTo(len(e.line))
+			}
+		case KeyDelete:
+			if !e.dismissSuggestion() {
+				e.deleteRange(e.cursor, e.nextGrapheme(), false)
+			}
+		case KeyPaste:
+			// The answer is a single line, the pasted line breaks cannot validate it
+			e.insert(key.pastedRunes(" "))
+		case KeyRune:
+			if done, err := e.handleRune(key); done {
+				return string(e.line), err
+			}
+		}
+	}
+}
+
+// handleRune inserts a character in the line or runs a Ctrl or Alt shortcut. It returns true when the prompt is over.
+func (e *lineEditor) handleRune(key KeyEvent) (bool, error) {
+	if key.isCharacter() {
+		e.insert([]rune{key.Rune})
+		return false, nil
+	}
+
+	if key.Modifiers == ModAlt {
+		switch key.Rune {
+		case 'b', 'B':
+			e.moveTo(e.previousWordStart())
+		case 'f', 'F':
+			e.moveTo(e.nextWordEnd())
+		}
+		return false, nil
+	}
+	if key.Modifiers != ModCtrl {
+		return false, nil
+	}
+
+	switch key.Rune {
+	case 'a':
+		e.moveTo(0)
+	case 'b':
+		e.moveTo(e.previousGrapheme())
+	case 'c':
+		e.finish()
+		return true, ErrInterrupted
+	case 'd':
+		if len(e.line) == 0 {
+			e.finish()
+			return true, ErrAborted
+		}
+		e.deleteRange(e.cursor, e.nextGrapheme(), false)
+	case 'e':
+		e.moveTo(len(e.line))
+	case 'f':
+		e.moveTo(e.nextGrapheme())
+	case 'k':
+		e.deleteRange(e.cursor, len(e.line), true)
+	case 'u':
+		e.deleteRange(0, e.cursor, true)
+	case 'w':
+		e.deleteRange(e.previousWordStart(), e.cursor, true)
+	case 'y':
+		e.insert(e.killed)
+	}
+
+	return false, nil
+}
+
+// complete replaces the line with the next candidate of the completer, or the previous one if step is negative
+func (e *lineEditor) complete(step int) {
+	if e.completer == nil {
+		return
+	}
+
+	if e.completion == nil {
+		candidates := e.completerCandidates()
+		if len(candidates) == 0 {
+			return
+		}
+
+		e.completion = &completion{candidates: candidates, index: -1}
+		if step < 0 {
+			e.completion.index = 0
+		}
+	}
+
+	count := len(e.completion.candidates)
+	e.completion.index = ((e.completion.index+step)%count + count) % count
+	e.line = []rune(e.completion.candidates[e.completion.index])
+	e.cursor = len(e.line)
+
+	if count == 1 {
+		// The completion can go on from the only candidate, for example in a subdirectory
+		e.completion = nil
+	}
+	e.redraw()
+}
+
+// completerCandidates returns the candidates of the completer for the current line
+func (e *lineEditor) completerCandidates() []string {
+	line := string(e.line)
+	if !e.candidatesFound || e.candidatesLine != line {
+		e.candidates = e.completer(line)
+		e.candidatesLine = line
+		e.candidatesFound = true
+	}
+
+	return e.candidates
+}
+
+// updateSuggestion finds the first candidate of the completer that starts with the line, to suggest its end
+func (e *lineEditor) updateSuggestion() {
+	e.suggestion = ``
+	if e.completer == nil || e.completion != nil || e.cursor != len(e.line) || len(e.line) == 0 || !e.suggestionShown {
+		return
+	}
+
+	line := string(e.line)
+	for _, candidate := range e.completerCandidates() {
+		if len(candidate) > len(line) && strings.HasPrefix(candidate, line) {
+			e.suggestion = candidate[len(line):]
+			return
+		}
+	}
+}
+
+// acceptSuggestion adds the suggested text at the end of the line, and returns false if there is no suggestion
+func (e *lineEditor) acceptSuggestion() bool {
+	if e.suggestion == `` {
+		return false
+	}
+
+	e.insert([]rune(e.suggestion))
+	return true
+}
+
+// dismissSuggestion hides the suggested text until the line is modified, and returns false if there is no suggestion
+func (e *lineEditor) dismissSuggestion() bool {
+	if e.suggestion == `` {
+		return false
+	}
+
+	e.suggestionShown = false
+	e.redraw()
+	return true
+}
+
+// insert adds some text at the position of the cursor
+func (e *lineEditor) insert(text []rune) {
+	if len(text) == 0 {
+		return
+	}
+
+	line := make([]rune, 0, len(e.line)+len(text))
+	line = append(line, e.line[:e.cursor]...)
+	line = append(line, text...)
+	e.line = append(line, e.line[e.cursor:]...)
+	e.cursor += len(text)
+	e.suggestionShown = true
+	e.redraw()
+}
+
+// deleteRange removes a part of the line, and keeps it to be yanked with Ctrl-Y if kill is true
+func (e *lineEditor) deleteRange(start int, end int, kill bool) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(e.line) {
+		end = len(e.line)
+	}
+	if start >= end {
+		return
+	}
+
+	if kill {
+		e.killed = append([]rune{}, e.line[start:end]...)
+	}
+	e.line = append(e.line[:start], e.line[end:]...)
+	e.cursor = start
+	e.suggestionShown = true
+	e.redraw()
+}
+
+// moveTo moves the cursor to a position in the line
+func (e *lineEditor) moveTo(position int) {
+	if position < 0 || position > len(e.line) || position == e.cursor {
+		return
+	}
+
+	e.cursor = position
+	e.redraw()
+}
+
+// previousGrapheme returns the position of the grapheme cluster before the cursor, so that a letter with combining
+// accents or an emoji is moved over and deleted as a whole
+func (e *lineEditor) previousGrapheme() int {
+	previous := 0
+	for _, boundary := range e.graphemeBoundaries() {
+		if boundary >= e.cursor {
+			break
+		}
+		previous = boundary
+	}
+
+	return previous
+}
+
+// nextGrapheme returns the position of the end of the grapheme cluster after the cursor
+func (e *lineEditor) nextGrapheme() int {
+	for _, boundary := range e.graphemeBoundaries() {
+		if boundary > e.cursor {
+			return boundary
+		}
+	}
+
+	return len(e.line)
+}
+
+// graphemeBoundaries returns the positions in the line where the grapheme clusters end
+func (e *lineEditor) graphemeBoundaries() []int {
+	boundaries := []int{}
+	position := 0
+	graphemes := uniseg.NewGraphemes(string(e.line))
+	for graphemes.Next() {
+		position += len(graphemes.Runes())
+		boundaries = append(boundaries, position)
+	}
+
+	return boundaries
+}
+
+// previousWordStart returns the position of the beginning of the word before the cursor
+func (e *lineEditor) previousWordStart() int {
+	position := e.cursor
+	for position > 0 && unicode.IsSpace(e.line[position-1]) {
+		position--
+	}
+	for position > 0 && !unicode.IsSpace(e.line[position-1]) {
+		position--
+	}
+
+	return position
+}
+
+// nextWordEnd returns the position of the end of the word after the cursor
+func (e *lineEditor) nextWordEnd() int {
+	position := e.cursor
+	for position < len(e.line) && unicode.IsSpace(e.line[position]) {
+		position++
+	}
+	for position < len(e.line) && !unicode.IsSpace(e.line[position]) {
+		position++
+	}
+
+	return position
+}
+
+// recallHistory replaces the line with an entry of the history. The index after the last entry restores the line being typed.
+func (e *lineEditor) recallHistory(index int) {
+	if index < 0 || index > len(e.history) || index == e.historyIndex {
+		return
+	}
+
+	if e.historyIndex == len(e.history) {
+		e.draft = e.line
+	}
+
+	e.historyIndex = index
+	if index == len(e.history) {
+		e.line = e.draft
+	} else {
+		e.line = []rune(e.history[index])
+	}
+	e.cursor = len(e.line)
+	e.redraw()
+}
+
+// redraw prints the prompt and the line again with the suggested completion, and puts the cursor at its position.
+// The line is printed from the row of the prompt, and the rows it does not cover anymore are cleared.
+func (e *lineEditor) redraw() {
+	e.updateSuggestion()
+	suggestion := e.suggestion
+	if suggestion != `` {
+		suggestion = e.console.applyStyle(suggestionStyle, suggestion)
+	}
+
+	out := e.console.out
+	if e.cursorRow > 0 {
+		fmt.Fprintf(out, "\033[%dA", e.cursorRow)
+	}
+	fmt.Fprintf(out, "\r%s%s%s\033[0J", e.prompt, string(e.line), suggestion)
+
+	width := e.terminalWidth()
+	promptWidth := displayedWidth(e.prompt)
+	end := promptWidth + styledprinter.StringWidth(string(e.line)+e.suggestion)
+	if end > 0 && end%width == 0 {
+		// The terminal only wraps before the next character, the cursor is moved to the next row to know where it is
+		fmt.Fprint(out, "\r\n")
+	}
+
+	position := promptWidth + styledprinter.StringWidth(string(e.line[:e.cursor]))
+	e.endRow, e.cursorRow = end/width, position/width
+	if e.endRow > e.cursorRow {
+		fmt.Fprintf(out, "\033[%dA", e.endRow-e.cursorRow)
+	}
+
+	column, endColumn := position%width, end%width
+	if e.endRow == e.cursorRow && column < endColumn {
+		fmt.Fprintf(out, "\033[%dD", endColumn-column)
+	} else if e.endRow != e.cursorRow {
+		fmt.Fprint(out, "\r")
+		if column > 0 {
+			fmt.Fprintf(out, "\033[%dC", column)
+		}
+	}
+}
+
+// finish moves the cursor to the row after the line, as the line break is not printed in raw mode
+func (e *lineEditor) finish() {
+	if e.endRow > e.cursorRow {
+		fmt.Fprintf(e.console.out, "\033[%dB", e.endRow-e.cursorRow)
+	}
+	fmt.Fprint(e.console.out, "\r\n")
+}
+
+// terminalWidth returns the number of columns of the terminal, after which the line wraps
+func (e *lineEditor) terminalWidth() int {
+	width, _ := e.console.getWinsize()
+	if width <= 0 {
+		return 1
+	}
+
+	return width
+}
+
+// ansiSequenceRegexp matches the escape sequences of the styles and of the hyperlinks, that take no space in the terminal
+var ansiSequenceRegexp = regexp.MustCompile("\033\\[[0-9;]*[A-Za-z]|\033\\]8;[^\033]*\033\\\\")
+
+// displayedWidth returns the number of columns needed to display a text that contains escape sequences
+func displayedWidth(text string) int {
+	return styledprinter.StringWidth(ansiSequenceRegexp.ReplaceAllString(text, ``))
+}
+
+// PathCompleter completes the input with the paths of the files and directories it can lead to.
+// Hidden files are only suggested once the input starts with a dot, and directories end with a separator.
+func PathCompleter(input string) []string {
+	directory, prefix := filepath.Split(input)
+	searchedDirectory := directory
+	if searchedDirectory == `` {
+		searchedDirectory = "."
+	}
+
+	entries, err := os.ReadDir(searchedDirectory)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+
+		candidate := directory + name
+		if entry.IsDir() {
+			candidate += string(filepath.Separator)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
+
+// loadHistory returns the previous answers, read from the history file the first time
+func (c *Console) loadHistory() []string {
+	if c.historyLoaded || c.historyFile == `` {
+		return c.history
+	}
+	c.historyLoaded = true
+
+	file, err := os.Open(c.historyFile)
+	if err != nil {
+		// The file does not exist yet
+		return c.history
+	}
+	defer file.Close()
+
+	var history []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != `` {
+			history = append(history, line)
+		}
+	}
+	c.history = append(history, c.history...)
+
+	return c.history
+}
+
+// addToHistory adds an answer at the end of the history, and saves it in the history file, unless it is the same
+// as the previous one. A failure to save the history does not prevent the prompt from succeeding.
+func (c *Console) addToHistory(answer string) {
+	if strings.TrimSpace(answer) == `` || strings.ContainsAny(answer, "\r\n") {
+		return
+	}
+	if len(c.history) > 0 && c.history[len(c.history)-1] == answer {
+		return
+	}
+
+	c.history = append(c.history, answer)
+	if c.historyFile == `` {
+		return
+	}
+
+	file, err := os.OpenFile(c.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	fmt.Fprintln(file, answer)
+}
@@ -0,0 +1,183 @@
+package styledconsole
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLineEditorShortcuts checks the cursor movements and the deletion shortcuts of the line editor
+func TestLineEditorShortcuts(t *testing.T) {
+	assert := assert.New(t)
+
+	answers := map[string]string{
+		"hello\x1b[D\x1b[DXY\r":                      "helXYlo",
+		"world\x01hello \x05!\r":                     "hello world!",
+		"one two three\x17\x17four\n":                "one four",
+		"one two\x1b[H\x1b[3~\x1b[F\x7f\r":           "ne tw",
+		"one two three\x1bb\x1bb\x0b\x05\x19\r":      "one two three",
+		"one two three\x1bb\x15\x05 \x19\r":          "three one two ",
+		"one two three\x01\x1bf\x1bf\x02\x02X\r":     "one tXwo three",
+		"abc\x1b[D\x1b[1;5P\x1b[Zd\x1b[7~\x06\x04\r": "adc",
+		"a\x1b[200~pasted\r\ntext\x1b[201~!\r":       "apasted text!",
+		"one two\x1b[1;5D\x1b[1;5DX\x1b[1;3CY\r":     "XoneY two",
+	}
+	for input, expected := range answers {
+		console := New(strings.NewReader(input), &bytes.Buffer{}, WithTerminal(true))
+		answer, err := console.Ask("Question", nil)
+		assert.Nil(err)
+		assert.Equal(expected, answer, "input %q", input)
+	}
+}
+
+// TestLineEditorWrapping checks a line wider than the terminal is redrawn from the row of the prompt,
+// and the cursor moves over the whole grapheme clusters
+func TestLineEditorWrapping(t *testing.T) {
+	assert := assert.New(t)
+
+	// " > " and 9 characters take 12 columns, the line wraps on a second row of the 10 columns
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader("abcdefghi\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x1b[DX\r"), out, WithTerminal(true), WithSize(10, 20))
+	answer, err := console.Ask("Question", nil)
+	assert.Nil(err)
+	assert.Equal("abcXdefghi", answer)
+
+	output := out.String()
+	assert.Contains(output, "\r > abcdefg\033[0J\r\n", "the cursor goes to the next row when the line fills the first one")
+	assert.Contains(output, "\033[1A\r > abcdefgh\033[0J", "the line is redrawn from the row of the prompt")
+	assert.Contains(output, "\r > abcdefghi\033[0J\033[1A\r\033[8C", "the cursor goes back to the first row")
+	assert.True(strings.HasSuffix(output, "\r > abcXdefghi\033[0J\033[1A\r\033[7C\033[1B\r\n\033[?2004l"), output)
+
+	// The letters with combining accents and the wide characters are moved over as a whole
+	answers := map[string]string{
+		"e\u0301\x1b[Dx\r":       "xe\u0301",
+		"ae\u0301\x7f\r":         "a",
+		"日本\x1b[D\x1b[3~x\r":     "日x",
+		"a👍🏽b\x1b[D\x1b[D\x04\r": "ab",
+	}
+	for input, expected := range answers {
+		console := New(strings.NewReader(input), &bytes.Buffer{}, WithTerminal(true))
+		answer, err := console.Ask("Question", nil)
+		assert.Nil(err)
+		assert.Equal(expected, answer, "input %q", input)
+	}
+
+	out.Reset()
+	console = New(strings.NewReader("日本\x1b[D\r"), out, WithTerminal(true))
+	_, err = console.Ask("Question", nil)
+	assert.Nil(err)
+	assert.Contains(out.String(), "\r > 日本\033[0J\033[2D")
+}
+
+// TestLineEditorHistory checks the previous answers can be recalled with the up and down arrows
+func TestLineEditorHistory(t *testing.T) {
+	assert := assert.New(t)
+	historyFile := filepath.Join(t.TempDir(), "history")
+	assert.Nil(os.WriteFile(historyFile, []byte("from file\n"), 0600))
+
+	console := New(
+		strings.NewReader("first\rsecond\r\x1b[A\x1b[A!\rdraft\x1b[A\x1b[B\x1b[B\x1b[A\x1b[A\x1b[A\x1b[A\r"),
+		&bytes.Buffer{},
+		WithTerminal(true),
+		WithHistoryFile(historyFile),
+	)
+
+	for _, expected := range []string{"first", "second", "first!", "from file"} {
+		answer, err := console.Ask("Question", nil)
+		assert.Nil(err)
+		assert.Equal(expected, answer)
+	}
+
+	content, err := os.ReadFile(historyFile)
+	assert.Nil(err)
+	assert.Equal("from file\nfirst\nsecond\nfirst!\nfrom file\n", string(content))
+
+	// The answers rejected by the validator and the repeated ones are not kept
+	console = New(strings.NewReader("typo\rvalid\rvalid\r"), &bytes.Buffer{}, WithTerminal(true), WithHistoryFile(historyFile))
+	for i := 0; i < 2; i++ {
+		answer, err := console.Ask("Question", func(answer string) bool { return answer != "typo" })
+		assert.Nil(err)
+		assert.Equal("valid", answer)
+	}
+
+	content, err = os.ReadFile(historyFile)
+	assert.Nil(err)
+	assert.Equal("from file\nfirst\nsecond\nfirst!\nfrom file\nvalid\n", string(content))
+}
+
+// TestLineEditorCompletion checks the completion cycles through the candidates, and the suggestion can be accepted or dismissed
+func TestLineEditorCompletion(t *testing.T) {
+	assert := assert.New(t)
+	completer := func(input string) []string {
+		var candidates []string
+		for _, branch := range []string{"main", "master", "feature/login", "feature/logout"} {
+			if strings.HasPrefix(branch, input) {
+				candidates = append(candidates, branch)
+			}
+		}
+		return candidates
+	}
+
+	answers := map[string]string{
+		"ma\r":                      "main",
+		"mas\x1b[3~\r":              "mas",
+		"fe\x1b[C/\r":               "feature/login/",
+		"ma\t\t\r":                  "master",
+		"ma\t\t\t\r":                "main",
+		"fe\x1b[Z\r":                "feature/logout",
+		"feature/logi\t\x7f\x7fX\r": "feature/logX",
+		"zz\t\r":                    "zz",
+	}
+	for input, expected := range answers {
+		console := New(strings.NewReader(input), &bytes.Buffer{}, WithTerminal(true))
+		answer, err := console.AskWithCompletion("Branch", completer)
+		assert.Nil(err)
+		assert.Equal(expected, answer, "input %q", input)
+	}
+
+	// The suggestion is printed after the cursor
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader("m\r"), out, WithTerminal(true), WithColorLevel(styledprinter.ColorLevelNone))
+	_, err := console.AskWithCompletion("Branch", completer)
+	assert.Nil(err)
+	assert.Contains(out.String(), "\r > main\033[0J\033[3D")
+
+	// Without a suggestion, no style is printed
+	out.Reset()
+	console = New(strings.NewReader("z\r"), out, WithTerminal(true), WithColorLevel(styledprinter.ColorLevel256))
+	_, err = console.AskWithCompletion("Branch", completer)
+	assert.Nil(err)
+	assert.Contains(out.String(), "\r > z\033[0J")
+
+	// The completer is only called when the line changes
+	calls := 0
+	console = New(strings.NewReader("fe\x1b[D\x1b[C\x1b[D\x1b[C\x1b[3~\r"), &bytes.Buffer{}, WithTerminal(true))
+	answer, err := console.AskWithCompletion("Branch", func(input string) []string {
+		calls++
+		return completer(input)
+	})
+	assert.Nil(err)
+	assert.Equal("fe", answer)
+	assert.Equal(2, calls)
+}
+
+// TestPathCompleter checks the paths of the filesystem are completed
+func TestPathCompleter(t *testing.T) {
+	assert := assert.New(t)
+	directory := t.TempDir()
+	assert.Nil(os.Mkdir(filepath.Join(directory, "config"), 0700))
+	assert.Nil(os.WriteFile(filepath.Join(directory, "config.yml"), nil, 0600))
+	assert.Nil(os.WriteFile(filepath.Join(directory, ".env"), nil, 0600))
+	assert.Nil(os.WriteFile(filepath.Join(directory, "config", "app.yml"), nil, 0600))
+
+	prefix := directory + string(filepath.Separator)
+	assert.Equal([]string{prefix + "config" + string(filepath.Separator), prefix + "config.yml"}, PathCompleter(prefix+"con"))
+	assert.Equal([]string{prefix + ".env"}, PathCompleter(prefix+"."))
+	assert.Equal([]string{prefix + filepath.Join("config", "app.yml")}, PathCompleter(prefix+"config"+string(filepath.Separator)))
+	assert.Empty(PathCompleter(prefix + "missing" + string(filepath.Separator)))
+}
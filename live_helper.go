@@ -0,0 +1,160 @@
+package styledconsole
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// liveWidget is a widget that is redrawn in place at the bottom of the output, like a progress bar or a spinner
+type liveWidget interface {
+	// lockAndHide locks the widget and erases it from the terminal. It returns false if the widget is not displayed anymore,
+	// in which case the widget is already unlocked.
+	lockAndHide() bool
+	// showAndUnlock draws the widget again and unlocks it
+	showAndUnlock()
+}
+
+// addLiveWidget registers a widget, so that the output of the console is printed above it.
+// It must not be called while the mutex of a widget is held.
+func (c *Console) addLiveWidget(widget liveWidget) {
+	c.liveMutex.Lock()
+	defer c.liveMutex.Unlock()
+
+	for _, w := range c.liveWidgets {
+		if w == widget {
+			return
+		}
+	}
+	c.liveWidgets = append(c.liveWidgets, widget)
+}
+
+// printAbove hides the live widgets, runs the print function and draws the widgets again below what was printed
+func (c *Console) printAbove(print func()) {
+	c.replaceLiveWidget(nil, func(bool) { print() })
+}
+
+// replaceLiveWidget is the same as printAbove, but the given widget is forgotten once the print function has run,
+// so that it is erased and replaced by what is printed without any other output in between.
+// The print function is told whether the widget was hidden, in which case the widget is locked while it runs.
+// The widget must not draw itself again in showAndUnlock() once it is stopped by the print function.
+func (c *Console) replaceLiveWidget(widget liveWidget, print func(hidden bool)) {
+	c.liveMutex.Lock()
+	defer c.liveMutex.Unlock()
+
+	// The widgets that are not displayed anymore are forgotten
+	var hidden []liveWidget
+	widgetHidden := false
+	for i := len(c.liveWidgets) - 1; i >= 0; i-- {
+		if c.liveWidgets[i].lockAndHide() {
+			hidden = append([]liveWidget{c.liveWidgets[i]}, hidden...)
+			widgetHidden = widgetHidden || c.liveWidgets[i] == widget
+		}
+	}
+
+	print(widgetHidden)
+
+	c.liveWidgets = nil
+	for _, w := range hidden {
+		w.showAndUnlock()
+		if w != widget {
+			c.liveWidgets = append(c.liveWidgets, w)
+		}
+	}
+}
+
+// Writer returns an io.Writer that prints above the progress bars and spinners of the console, to be given to
+// log.SetOutput() or to a slog.Handler. The lines are printed once they are complete.
+func (c *Console) Writer() io.Writer {
+	return &liveWriter{console: c}
+}
+
+// liveWriter prints complete lines above the live widgets of a console
+type liveWriter struct {
+	mutex   sync.Mutex
+	console *Console
+	pending []byte
+}
+
+// Write prints the complete lines of p, and keeps the end of the last line until it is complete
+func (w *liveWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending = append(w.pending, p...)
+	end := bytes.LastIndexByte(w.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+
+	lines := w.pending[:end+1]
+	var err error
+	w.console.printAbove(func() {
+		_, err = w.console.out.Write(lines)
+	})
+	w.pending = append([]byte{}, w.pending[end+1:]...)
+
+	if err != nil {
+		return 0, fmt.Errorf("there was an error while writing the lines: %w", err)
+	}
+	return len(p), nil
+}
+
+func (b *ProgressBar) lockAndHide() bool {
+	b.mutex.Lock()
+	if b.finished {
+		b.mutex.Unlock()
+		return false
+	}
+
+	fmt.Fprint(b.console.out, "\033[1000D\033[0K")
+	return true
+}
+
+func (b *ProgressBar) showAndUnlock() {
+	b.redraw(true)
+	b.mutex.Unlock()
+}
+
+func (m *MultiProgress) lockAndHide() bool {
+	m.mutex.Lock()
+
+	running := false
+	for _, bar := range m.bars {
+		running = running || !bar.finished
+	}
+	if !running {
+		m.mutex.Unlock()
+		return false
+	}
+
+	if m.drawnLines > 0 {
+		fmt.Fprintf(m.console.out, "\033[%dA\033[1000D\033[0J", m.drawnLines)
+		m.drawnLines = 0
+	}
+	return true
+}
+
+func (m *MultiProgress) showAndUnlock() {
+	m.redraw(true)
+	m.mutex.Unlock()
+}
+
+func (s *Spinner) lockAndHide() bool {
+	s.mutex.Lock()
+	if s.stop == nil {
+		s.mutex.Unlock()
+		return false
+	}
+
+	fmt.Fprint(s.console.out, "\033[1000D\033[0K")
+	return true
+}
+
+func (s *Spinner) showAndUnlock() {
+	if s.stop != nil {
+		s.draw()
+	}
+	s.mutex.Unlock()
+}
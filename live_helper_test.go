@@ -0,0 +1,117 @@
+package styledconsole
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPrintAboveProgressBar checks the output of the console is printed above an active progress bar
+func TestPrintAboveProgressBar(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithSize(34, 20), WithColorLevel(styledprinter.ColorLevelNone))
+
+	bar := console.NewProgressBar(2)
+	out.Reset()
+	console.Text("some text")
+	assert.Equal("\033[1000D\033[0Ksome text\n\033[1000D  0/2 [>-------------------]   0%\033[0K", out.String())
+
+	// Once finished, the bar is left as-is
+	bar.Finish()
+	out.Reset()
+	console.Text("some text")
+	assert.Equal("some text\n", out.String())
+}
+
+// TestPrintAboveMultiProgress checks the output of the console is printed above the bars of a MultiProgress
+func TestPrintAboveMultiProgress(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithSize(34, 20), WithColorLevel(styledprinter.ColorLevelNone))
+
+	multi := console.NewMultiProgress()
+	multi.NewProgressBar(2)
+	multi.NewProgressBar(2)
+	out.Reset()
+	console.Listing([]string{"careful"})
+	assert.Equal(
+		"\033[2A\033[1000D\033[0J * careful\n"+
+			"\033[1000D  0/2 [>-------------------]   0%\033[0K\n\033[1000D  0/2 [>-------------------]   0%\033[0K\n",
+		out.String(),
+	)
+}
+
+// TestPrintAboveSpinner checks the lines of a logger are printed above a running spinner
+func TestPrintAboveSpinner(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithColorLevel(styledprinter.ColorLevelNone))
+
+	spinner := console.NewSpinner("Loading")
+	spinner.SetFrames([]string{"*"})
+	spinner.Start()
+	defer spinner.Stop()
+
+	logger := log.New(console.Writer(), "", 0)
+	writer := console.Writer()
+
+	out.Reset()
+	logger.Print("first line")
+	fmt.Fprint(writer, "incomplete ")
+	assert.Equal("\033[1000D\033[0Kfirst line\n\033[1000D * Loading\033[0K", out.String())
+
+	out.Reset()
+	fmt.Fprint(writer, "line\nend")
+	assert.Equal("\033[1000D\033[0Kincomplete line\n\033[1000D * Loading\033[0K", out.String())
+}
+
+// TestSpinnerEndAboveProgressBar checks the final line of a spinner is printed above a running progress bar
+func TestSpinnerEndAboveProgressBar(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithSize(34, 20), WithColorLevel(styledprinter.ColorLevelNone))
+
+	bar := console.NewProgressBar(2)
+	defer bar.Finish()
+	spinner := console.NewSpinner("Loading")
+	spinner.Start()
+
+	out.Reset()
+	spinner.Success("Loaded")
+	assert.Equal(
+		"\033[1000D\033[0K\033[1000D\033[0K\033[?25h\033[?0c ✔ Loaded\n\033[1000D  0/2 [>-------------------]   0%\033[0K",
+		out.String(),
+	)
+}
+
+// TestSpinnerEndWhilePrinting checks the spinner is always erased before a line is printed, even while it is ending
+func TestSpinnerEndWhilePrinting(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithColorLevel(styledprinter.ColorLevelNone))
+
+	for i := 0; i < 50; i++ {
+		spinner := console.NewSpinner("Loading")
+		spinner.Start()
+
+		printed := make(chan struct{})
+		go func() {
+			defer close(printed)
+			console.Text("line")
+		}()
+		spinner.Success("Loaded")
+		<-printed
+	}
+
+	// Every frame of the spinner is erased or replaced by the next one, nothing is printed after it on the same row
+	output := out.String()
+	assert.Equal(strings.Count(output, "Loading\033[0K"), strings.Count(output, "Loading\033[0K\033[1000D"), output)
+	assert.Equal(50, strings.Count(output, "line\n"))
+	assert.Equal(50, strings.Count(output, "✔ Loaded\n"))
+}
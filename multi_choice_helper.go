@@ -0,0 +1,199 @@
+package styledconsole
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+)
+
+// askMultipleChoices prints a list of choices and lets the user select between min and max of them.
+// When the answer timeout of the console is over, the default choices are returned.
+func (c *Console) askMultipleChoices(ctx context.Context, label string, choices []string, defaults []int, min int, max int) ([]string, error) {
+	if len(choices) == 0 {
+		return nil, errors.New("the question has no choices")
+	}
+	if max <= 0 || max > len(choices) {
+		max = len(choices)
+	}
+	if min > max {
+		return nil, fmt.Errorf("cannot select at least %d choices out of %d", min, max)
+	}
+	if !c.isTerm() {
+		return nil, errors.New("cannot open an interacive prompt outside of a TTY")
+	}
+
+	restoreTerminal, err := c.makeRaw()
+	if err != nil {
+		return nil, fmt.Errorf("there was an error switching terminal to raw mode: %w", err)
+	}
+	defer restoreTerminal()
+	disableMouse := c.enableMouse()
+	defer disableMouse()
+
+	promptCtx, cancel := c.promptContext(ctx)
+	defer cancel()
+
+	selected := make([]bool, len(choices))
+	selectedCount := 0
+	for _, index := range defaults {
+		if index >= 0 && index < len(choices) && !selected[index] && selectedCount < max {
+			selected[index] = true
+			selectedCount++
+		}
+	}
+	defaultSelected, defaultCount := append([]bool{}, selected...), selectedCount
+
+	confirm := func() []string {
+		answers := []string{}
+		for i, choice := range choices {
+			if selected[i] {
+				answers = append(answers, choice)
+			}
+		}
+		return answers
+	}
+
+	// stopped returns the answer of a prompt that failed, which is the default selection if the answer timeout is over
+	stopped := func(err error) ([]string, error) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if isAnswerTimeout(ctx, err) {
+			if defaultCount >= min {
+				selected, selectedCount = defaultSelected, defaultCount
+				return confirm(), nil
+			}
+			return nil, ErrTimeout
+		}
+		return nil, err
+	}
+
+	width, height, err := c.waitForTerminalSize(promptCtx)
+	if err != nil {
+		return stopped(err)
+	}
+
+	printableChoices := []string{}
+	for _, choice := range choices {
+		printableChoices = append(printableChoices, styledprinter.Truncate(choice, width-8, "…"))
+	}
+	list := newChoiceList(printableChoices, height, 0)
+
+	formatChoice := func(index int, highlighted bool) string {
+		mark := "[ ]"
+		if selected[index] {
+			mark = "[x]"
+		}
+
+		return c.formatClosedQuestionChoice(fmt.Sprintf("%s %s", mark, printableChoices[index]), highlighted)
+	}
+
+	// toggle selects or deselects a choice, and returns an error message if too many choices would be selected
+	toggle := func(index int) string {
+		if selected[index] {
+			selected[index] = false
+			selectedCount--
+		} else if selectedCount < max {
+			selected[index] = true
+			selectedCount++
+		} else {
+			return c.applyStyle(redStyle, fmt.Sprintf("You cannot select more than %d choices.", max))
+		}
+		return ``
+	}
+
+	// The row of the label is needed to find the clicked choice, it is asked to the terminal after every redraw
+	labelRow := 0
+
+	// The list can be resized after it is drawn, the cursor is put back below the lines that are on the screen
+	drawnLines := 0
+	footer := ``
+	redraw := true
+	c.hideCursor()
+	defer func() {
+		// Whatever the answer, the cursor is put back below the list
+		fmt.Fprintf(c.out, "\033[%dB\033[1000D", drawnLines+1)
+		c.showCursor()
+	}()
+	for {
+		if redraw {
+			drawnLines = c.printChoiceList(label, list, footer, formatChoice)
+			footer = ``
+			if c.mouse {
+				_ = c.keys.RequestCursorPosition(c.out)
+			}
+		}
+		redraw = true
+
+		key, err := c.keys.ReadKeyContext(promptCtx)
+
+		// Re-parse the height in case the user resized their terminal
+		_, height = c.getWinsize()
+		list.resize(height)
+
+		if err == io.EOF {
+			if selectedCount >= min {
+				drawnLines = c.printChoiceList(label, list, ``, formatChoice)
+				return confirm(), nil
+			}
+
+			return nil, errors.New("error parsing user activity from Stdin (EOF)")
+		}
+		if err != nil {
+			answers, err := stopped(fmt.Errorf("there was an error reading user input: %w", err))
+			if err == nil {
+				drawnLines = c.printChoiceList(label, list, ``, formatChoice)
+			}
+			return answers, err
+		}
+
+		switch {
+		case key.Key == KeyUp:
+			list.moveUp()
+		case key.Key == KeyDown:
+			list.moveDown()
+		case key.Key == KeyRune && key.Rune == ' ' && key.Modifiers == 0:
+			footer = toggle(list.highlightedIndex)
+		case key.isCharacter() && (key.Rune == 'a' || key.Rune == 'A'):
+			if selectedCount > 0 && (selectedCount == len(choices) || selectedCount == max) {
+				selected = make([]bool, len(choices))
+				selectedCount = 0
+			} else if max < len(choices) {
+				footer = c.applyStyle(redStyle, fmt.Sprintf("You cannot select more than %d choices.", max))
+			} else {
+				for i := range selected {
+					selected[i] = true
+				}
+				selectedCount = len(choices)
+			}
+		case key.Key == KeyEnter:
+			if selectedCount >= min {
+				drawnLines = c.printChoiceList(label, list, ``, formatChoice)
+				return confirm(), nil
+			}
+
+			footer = c.applyStyle(redStyle, fmt.Sprintf("You must select at least %d choices.", min))
+		case key.Key == KeyCursorPosition:
+			labelRow = key.Y
+			redraw = false
+		case key.Key == KeyMouse && (key.Button == MouseWheelUp || key.Button == MouseWheelDown):
+			list.scrollBy(key.Button)
+		case key.Key == KeyMouse && key.Button == MouseLeft && labelRow > 0:
+			if index := list.click(key.Y - labelRow - 1); index >= 0 {
+				footer = toggle(index)
+			} else {
+				redraw = false
+			}
+		case key.isCtrl('c'):
+			return nil, ErrInterrupted
+		case key.isCtrl('d'):
+			return nil, ErrAborted
+		default:
+			// The other keys, like the release of a mouse button, change nothing
+			redraw = false
+		}
+	}
+}
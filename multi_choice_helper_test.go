@@ -0,0 +1,157 @@
+package styledconsole
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMultiChoice checks several choices can be toggled and confirmed
+func TestMultiChoice(t *testing.T) {
+	assert := assert.New(t)
+	choices := []string{"api", "worker", "frontend", "database"}
+
+	answers := map[string][]string{
+		"\r":                                  {"worker"},
+		" \r":                                 {"api", "worker"},
+		"\x1b[B \x1b[B \x1b[A\x1b[A\x1b[A \r": {"frontend", "database"},
+		"a\r":                                 {"api", "worker", "frontend", "database"},
+		"aa\r":                                {},
+		"\x1b[B a\r":                          {"api", "worker", "frontend", "database"},
+		"\x1b[200~ \r\x1b[201~ \r":            {"api", "worker"},
+	}
+	for input, expected := range answers {
+		console := New(strings.NewReader(input), &bytes.Buffer{}, WithTerminal(true), WithSize(40, 20))
+		answer, err := console.MultiChoice("Services", choices, []int{1})
+		assert.Nil(err)
+		assert.Equal(expected, answer, "input %q", input)
+	}
+}
+
+// TestMultiChoiceWithLimits checks the amount of selected choices is constrained
+func TestMultiChoiceWithLimits(t *testing.T) {
+	assert := assert.New(t)
+	choices := []string{"api", "worker", "frontend", "database"}
+
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader("\r \x1b[B \x1b[B \r"), out, WithTerminal(true), WithSize(40, 20))
+	answer, err := console.MultiChoiceWithLimits("Services", choices, nil, 1, 2)
+	assert.Nil(err)
+	assert.Equal([]string{"api", "worker"}, answer)
+	assert.Contains(out.String(), "You must select at least 1 choices.")
+	assert.Contains(out.String(), "You cannot select more than 2 choices.")
+
+	// Without a terminal or with impossible limits, the question cannot be asked
+	_, err = New(strings.NewReader("\r"), out).MultiChoice("Services", choices, nil)
+	assert.NotNil(err)
+	_, err = console.MultiChoiceWithLimits("Services", choices, nil, 3, 2)
+	assert.NotNil(err)
+	_, err = console.MultiChoice("Services", nil, nil)
+	assert.NotNil(err)
+}
+
+// TestChoice checks a single choice can be selected in the list
+func TestChoice(t *testing.T) {
+	assert := assert.New(t)
+	choices := []string{"api", "worker", "frontend", "database"}
+
+	console := New(strings.NewReader("\x1b[B\x1b[B\r\x1b[A\r"), &bytes.Buffer{}, WithTerminal(true), WithSize(40, 20))
+	answer, err := console.Choice("Service", choices)
+	assert.Nil(err)
+	assert.Equal("frontend", answer)
+
+	answer, err = console.ChoiceWithDefault("Service", choices, 1)
+	assert.Nil(err)
+	assert.Equal("api", answer)
+}
+
+// resizingReader changes the height of the console before returning its input one byte at a time, like a terminal resized by the user
+type resizingReader struct {
+	console *Console
+	height  int
+	input   string
+}
+
+func (r *resizingReader) Read(p []byte) (int, error) {
+	if r.input == `` {
+		return 0, io.EOF
+	}
+
+	r.console.height = r.height
+	n := copy(p[:1], r.input)
+	r.input = r.input[n:]
+	return n, nil
+}
+
+// TestChoiceResized checks the cursor is put back below the list that was drawn, even if the terminal was resized since
+func TestChoiceResized(t *testing.T) {
+	assert := assert.New(t)
+	many := []string{}
+	for i := 0; i < 30; i++ {
+		many = append(many, fmt.Sprintf("choice %d", i))
+	}
+
+	// The 12 lines drawn below the label are a window of 10 choices, the first line and the last one
+	out := &bytes.Buffer{}
+	reader := &resizingReader{height: 8, input: "\r"}
+	console := New(reader, out, WithTerminal(true), WithSize(40, 20))
+	reader.console = console
+	answer, err := console.Choice("Choice", many)
+	assert.Nil(err)
+	assert.Equal("choice 0", answer)
+	assert.Contains(out.String(), "\033[12A\033[1000D\033[13B\033[1000D")
+
+	// The footer printed below the choices is counted
+	out.Reset()
+	reader = &resizingReader{height: 20, input: "\r\x03"}
+	console = New(reader, out, WithTerminal(true), WithSize(40, 8))
+	reader.console = console
+	_, err = console.MultiChoiceWithLimits("Choices", many, nil, 1, 0)
+	assert.Equal(ErrInterrupted, err)
+	assert.True(strings.HasSuffix(out.String(), "\033[13A\033[1000D\033[14B\033[1000D\033[?25h\033[?0c\033[?2004l"), out.String())
+}
+
+// TestChoiceMouse checks the choices can be clicked and scrolled once the row of the label is known
+func TestChoiceMouse(t *testing.T) {
+	assert := assert.New(t)
+	choices := []string{"api", "worker", "frontend", "database"}
+
+	// The label is on the 5th row, so the choices start on the 6th one
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader("\x1b[<0;10;8M\x1b[5;1R\x1b[<0;10;8M"), out, WithTerminal(true), WithSize(40, 20), WithMouse(true))
+	answer, err := console.Choice("Service", choices)
+	assert.Nil(err)
+	assert.Equal("frontend", answer)
+	assert.Contains(out.String(), "\033[?1000h\033[?1006h")
+	assert.Contains(out.String(), "\033[6n")
+	assert.True(strings.HasSuffix(out.String(), "\033[?1006l\033[?1000l\033[?2004l"))
+
+	// The list is only redrawn once per click, not when the button is released
+	out.Reset()
+	console = New(strings.NewReader("\x1b[5;1R\x1b[<0;10;6M\x1b[<0;10;6m\x1b[<0;10;9M\x1b[<0;10;9m\r"), out, WithTerminal(true), WithSize(40, 20), WithMouse(true))
+	answers, err := console.MultiChoice("Services", choices, []int{0})
+	assert.Nil(err)
+	assert.Equal([]string{"database"}, answers)
+	assert.Equal(3, strings.Count(out.String(), "\033[6n"))
+
+	// The wheel scrolls the list, the highlighted choice following it
+	many := []string{}
+	for i := 0; i < 30; i++ {
+		many = append(many, fmt.Sprintf("choice %d", i))
+	}
+	console = New(strings.NewReader("\x1b[<65;1;1M\x1b[<65;1;1M\x1b[<65;1;1M\x1b[<64;1;1M\r"), &bytes.Buffer{}, WithTerminal(true), WithSize(40, 20), WithMouse(true))
+	answer, err = console.Choice("Choice", many)
+	assert.Nil(err)
+	assert.Equal("choice 4", answer)
+
+	// Without the option, the mouse is not enabled
+	out.Reset()
+	console = New(strings.NewReader("\r"), out, WithTerminal(true), WithSize(40, 20))
+	_, err = console.Choice("Service", choices)
+	assert.Nil(err)
+	assert.NotContains(out.String(), "\033[?1000h")
+}
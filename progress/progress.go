@@ -0,0 +1,93 @@
+// Package progress displays the advancement of byte transfers, by wrapping an io.Reader or an io.Writer with a progress bar
+package progress
+
+import (
+	"io"
+
+	"github.com/corentindeboisset/styledconsole"
+)
+
+// Reader advances a progress bar with the bytes that are read, and finishes it at the end of the reader
+type Reader struct {
+	reader io.Reader
+	bar    *styledconsole.ProgressBar
+}
+
+// NewReader displays a progress bar on the standard output, that follows the bytes read from r.
+// If the size of the content is unknown, total should be 0 or negative to display an indeterminate bar.
+func NewReader(r io.Reader, total int64) *Reader {
+	return WrapReader(r, styledconsole.NewBytesProgressBar(total))
+}
+
+// WrapReader advances the given bar with the bytes read from r, which is useful to display the bar on another Console
+// or in a MultiProgress. The bar should be created with NewBytesProgressBar().
+func WrapReader(r io.Reader, bar *styledconsole.ProgressBar) *Reader {
+	return &Reader{reader: r, bar: bar}
+}
+
+// Read reads from the underlying reader and advances the bar
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.bar.AdvanceBy(int64(n))
+	if err == io.EOF {
+		r.bar.Finish()
+	}
+
+	return n, err
+}
+
+// Close finishes the bar, and closes the underlying reader if it is an io.Closer
+func (r *Reader) Close() error {
+	r.bar.Finish()
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Bar returns the progress bar, to change its format or its message
+func (r *Reader) Bar() *styledconsole.ProgressBar {
+	return r.bar
+}
+
+// Writer advances a progress bar with the bytes that are written
+type Writer struct {
+	writer io.Writer
+	bar    *styledconsole.ProgressBar
+}
+
+// NewWriter displays a progress bar on the standard output, that follows the bytes written to w.
+// If the size of the content is unknown, total should be 0 or negative to display an indeterminate bar, that is finished by Close().
+func NewWriter(w io.Writer, total int64) *Writer {
+	return WrapWriter(w, styledconsole.NewBytesProgressBar(total))
+}
+
+// WrapWriter advances the given bar with the bytes written to w, which is useful to display the bar on another Console
+// or in a MultiProgress. The bar should be created with NewBytesProgressBar().
+func WrapWriter(w io.Writer, bar *styledconsole.ProgressBar) *Writer {
+	return &Writer{writer: w, bar: bar}
+}
+
+// Write writes to the underlying writer and advances the bar
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.bar.AdvanceBy(int64(n))
+
+	return n, err
+}
+
+// Close finishes the bar, and closes the underlying writer if it is an io.Closer
+func (w *Writer) Close() error {
+	w.bar.Finish()
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Bar returns the progress bar, to change its format or its message
+func (w *Writer) Bar() *styledconsole.ProgressBar {
+	return w.bar
+}
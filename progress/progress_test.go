@@ -0,0 +1,68 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/corentindeboisset/styledconsole"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReader checks the bar follows the bytes that are read
+func TestReader(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := styledconsole.New(strings.NewReader(""), out)
+
+	content := strings.Repeat("a", 3*1024)
+	reader := WrapReader(strings.NewReader(content), console.NewProgressBarWithFormat(len(content), "%current_bytes%/%max_bytes%"))
+
+	buffer := make([]byte, 1536)
+	_, err := io.ReadFull(reader, buffer)
+	assert.Nil(err)
+	_, err = io.ReadFull(reader, buffer)
+	assert.Nil(err)
+	n, err := reader.Read(buffer)
+	assert.Equal(0, n)
+	assert.Equal(io.EOF, err)
+
+	assert.Equal("0 B/3.0 KiB\n1.5 KiB/3.0 KiB\n3.0 KiB/3.0 KiB\n", out.String())
+
+	// The bytes format shows the throughput
+	out.Reset()
+	reader = WrapReader(strings.NewReader(content), console.NewBytesProgressBar(int64(len(content))))
+	assert.Nil(reader.Close())
+	assert.Equal(
+		"        0 B/3.0 KiB   [>-------------------]   0% --\n    3.0 KiB/3.0 KiB   [===================>] 100% --\n",
+		out.String(),
+	)
+
+	// The sizes over 2 GiB are kept, even on 32-bit platforms
+	out.Reset()
+	bar := console.NewBytesProgressBar(6 << 30)
+	bar.SetFormat("%current_bytes%/%max_bytes% %percent%%")
+	bar.AdvanceBy(3 << 30)
+	assert.True(strings.HasSuffix(out.String(), "\n3.0 GiB/6.0 GiB 50%\n"))
+	bar.SetProgress64(9 << 29)
+	assert.True(strings.HasSuffix(out.String(), "\n4.5 GiB/6.0 GiB 75%\n"))
+}
+
+// TestWriter checks the bar follows the bytes that are written, and is finished when the writer is closed
+func TestWriter(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := styledconsole.New(strings.NewReader(""), out)
+
+	destination := &bytes.Buffer{}
+	writer := WrapWriter(destination, console.NewProgressBarWithFormat(0, "%current_bytes%"))
+
+	n, err := io.Copy(writer, strings.NewReader(strings.Repeat("a", 2048)))
+	assert.Nil(err)
+	assert.Equal(int64(2048), n)
+	assert.Nil(writer.Close())
+
+	assert.Equal(2048, destination.Len())
+	assert.Equal("0 B\n2.0 KiB\n", out.String())
+}
@@ -0,0 +1,308 @@
+package styledconsole
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+)
+
+// The preset formats of a progress bar, see ProgressBar.SetFormat()
+const (
+	ProgressFormatNormal      = "normal"
+	ProgressFormatVerbose     = "verbose"
+	ProgressFormatVeryVerbose = "very_verbose"
+	ProgressFormatDebug       = "debug"
+	// ProgressFormatBytes displays the steps as a number of bytes, for file copies and downloads
+	ProgressFormatBytes = "bytes"
+)
+
+var (
+	progressFormats = map[string]string{
+		ProgressFormatNormal:      "  %current%/%max% [%bar%] %percent:3s%%",
+		ProgressFormatVerbose:     "  %current%/%max% [%bar%] %percent:3s%% %elapsed:6s%",
+		ProgressFormatVeryVerbose: "  %current%/%max% [%bar%] %percent:3s%% %elapsed:6s%/%estimated:-6s%",
+		ProgressFormatDebug:       "  %current%/%max% [%bar%] %percent:3s%% %elapsed:6s%/%estimated:-6s% %memory:6s%",
+		ProgressFormatBytes:       "  %current_bytes:9s%/%max_bytes:-9s% [%bar%] %percent:3s%% %byte_rate%",
+
+		// Formats of the indeterminate bars
+		ProgressFormatNormal + "_nomax":      "  %current% [%bar%]",
+		ProgressFormatVerbose + "_nomax":     "  %current% [%bar%] %elapsed:6s%",
+		ProgressFormatVeryVerbose + "_nomax": "  %current% [%bar%] %elapsed:6s%",
+		ProgressFormatDebug + "_nomax":       "  %current% [%bar%] %elapsed:6s% %memory:6s%",
+		ProgressFormatBytes + "_nomax":       "  %current_bytes:9s% [%bar%] %byte_rate%",
+	}
+	placeholderRegexp = regexp.MustCompile(`%([a-z_]+)(?::([^%]+))?%`)
+)
+
+// SetFormat changes how the bar is displayed. The format is either the name of a preset (see ProgressFormatNormal and the
+// other constants) or a text containing placeholders:
+//   - %current% and %max%: the amount of steps that are done, and the total amount of steps
+//   - %bar%: the bar itself
+//   - %percent%: the percentage of the steps that are done
+//   - %elapsed%, %remaining% and %estimated%: the time since the start, the estimated time left, and the estimated total time
+//   - %rate%: the amount of steps done per second
+//   - %current_bytes%, %max_bytes% and %byte_rate%: the same values as %current%, %max% and %rate%, as a number of bytes
+//   - %memory%: the memory allocated by the program
+//   - %message%: the message given to SetMessage()
+//
+// The presets of an indeterminate bar do not display the total amount of steps nor the estimated times. In a custom format,
+// %max%, %max_bytes%, %percent%, %remaining% and %estimated% display "?" for such a bar, and %bar% shows a bouncing block.
+// A placeholder can be followed by a fmt verb to pad its value, like "%elapsed:6s%" or "%message:-20s%".
+func (b *ProgressBar) SetFormat(format string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.format = format
+	b.refresh()
+}
+
+// SetMessage changes the text displayed in place of the %message% placeholder
+func (b *ProgressBar) SetMessage(message string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.message = message
+	b.refresh()
+}
+
+// SetBarCharacters changes the characters used to draw the bar: full for the steps that are done,
+// progress for the current step and empty for the steps that are left.
+func (b *ProgressBar) SetBarCharacters(full string, progress string, empty string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.barFull, b.barProgress, b.barEmpty = full, progress, empty
+	b.refresh()
+}
+
+// SetBarWidth changes the amount of characters used to draw the bar, without the progress character.
+// By default, or with a width of 0, the bar takes the space left on the row of the terminal.
+// In any case, the bar is shrunk if the row is too narrow.
+func (b *ProgressBar) SetBarWidth(width int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if width >= 0 {
+		b.barWidth = width
+		b.refresh()
+	}
+}
+
+// refresh redraws the bar after its appearance was changed, the mutex must be held.
+// Without a terminal, the change will be visible on the next printed line.
+func (b *ProgressBar) refresh() {
+	if !b.finished && b.console.isTerm() {
+		b.redraw(true)
+	}
+}
+
+// resolvedFormat returns the format of the bar, with the presets replaced by their value
+func (b *ProgressBar) resolvedFormat() string {
+	if b.total == 0 {
+		if preset, ok := progressFormats[b.format+"_nomax"]; ok {
+			return preset
+		}
+	}
+	if preset, ok := progressFormats[b.format]; ok {
+		return preset
+	}
+
+	return b.format
+}
+
+// render replaces the placeholders of the format of the bar, without fitting it in the terminal. The mutex must be held.
+func (b *ProgressBar) render(elapsed time.Duration) string {
+	barWidth := b.barWidth
+	if barWidth == 0 {
+		barWidth = progressBarLength
+	}
+
+	return b.renderWith(elapsed, barWidth, b.message)
+}
+
+// fit renders the bar so that it fits in a row of the given width, the mutex must be held.
+// The bar takes the space left by the other placeholders, and the message is truncated if there is not enough space.
+func (b *ProgressBar) fit(elapsed time.Duration, rowWidth int) string {
+	available := rowWidth - styledprinter.StringWidth(b.renderWith(elapsed, 0, b.message))
+	barWidth := progressBarMaxLength
+	if b.barWidth > 0 {
+		barWidth = b.barWidth
+	}
+	if available < barWidth {
+		barWidth = available
+	}
+	if barWidth < progressBarMinLength {
+		barWidth = progressBarMinLength
+	}
+
+	line := b.renderWith(elapsed, barWidth, b.message)
+	if overflow := styledprinter.StringWidth(line) - rowWidth; overflow > 0 && b.message != `` {
+		messageWidth := styledprinter.StringWidth(b.message) - overflow
+		if messageWidth < 0 {
+			messageWidth = 0
+		}
+		line = b.renderWith(elapsed, barWidth, styledprinter.Truncate(b.message, messageWidth, "…"))
+	}
+
+	return styledprinter.Truncate(line, rowWidth, ``)
+}
+
+// renderWith replaces the placeholders of the format of the bar, with the given bar width and message
+func (b *ProgressBar) renderWith(elapsed time.Duration, barWidth int, message string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(b.resolvedFormat(), func(placeholder string) string {
+		match := placeholderRegexp.FindStringSubmatch(placeholder)
+		value, ok := b.placeholderValue(match[1], elapsed, barWidth, message)
+		if !ok {
+			return placeholder
+		}
+
+		if match[2] != `` {
+			return fmt.Sprintf("%"+match[2], value)
+		}
+		return value
+	})
+}
+
+// placeholderValue returns the value of a placeholder of the format, or false if the placeholder is unknown
+func (b *ProgressBar) placeholderValue(name string, elapsed time.Duration, barWidth int, message string) (string, bool) {
+	switch name {
+	case "current":
+		maxDigits := len(strconv.FormatInt(b.total, 10))
+		return fmt.Sprintf("%-"+strconv.Itoa(maxDigits)+"d", b.done), true
+	case "max":
+		if b.total == 0 {
+			return "?", true
+		}
+		return strconv.FormatInt(b.total, 10), true
+	case "bar":
+		return b.buildBar(elapsed, barWidth), true
+	case "percent":
+		ratio := b.ratio()
+		if ratio < 0 {
+			return "?", true
+		}
+		return strconv.Itoa(int(ratio * 100)), true
+	case "elapsed":
+		return formatDuration(elapsed), true
+	case "remaining":
+		if b.total == 0 {
+			return "?", true
+		}
+		if b.done == 0 {
+			return "--", true
+		}
+		return formatDuration(b.estimate(elapsed) - elapsed), true
+	case "estimated":
+		if b.total == 0 {
+			return "?", true
+		}
+		if b.done == 0 {
+			return "--", true
+		}
+		return formatDuration(b.estimate(elapsed)), true
+	case "rate":
+		if elapsed < time.Second {
+			return "--", true
+		}
+		return fmt.Sprintf("%.1f/s", float64(b.done)/elapsed.Seconds()), true
+	case "current_bytes":
+		return formatBytes(uint64(b.done)), true
+	case "max_bytes":
+		if b.total == 0 {
+			return "?", true
+		}
+		return formatBytes(uint64(b.total)), true
+	case "byte_rate":
+		if elapsed < time.Second {
+			return "--", true
+		}
+		return formatBytes(uint64(float64(b.done)/elapsed.Seconds())) + "/s", true
+	case "memory":
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return formatBytes(stats.Alloc), true
+	case "message":
+		return message, true
+	}
+
+	return ``, false
+}
+
+// ratio returns the part of the steps that are done, between 0 and 1, or -1 for an indeterminate bar
+func (b *ProgressBar) ratio() float64 {
+	if b.total <= 0 {
+		return -1
+	}
+
+	return float64(b.done) / float64(b.total)
+}
+
+// estimate returns the estimated time needed to do all the steps
+func (b *ProgressBar) estimate(elapsed time.Duration) time.Duration {
+	return time.Duration(float64(elapsed) / b.ratio())
+}
+
+// buildBar draws the bar with its characters. An indeterminate bar shows a block bouncing with time.
+func (b *ProgressBar) buildBar(elapsed time.Duration, width int) string {
+	if b.total == 0 {
+		return b.buildBouncingBar(elapsed, width)
+	}
+
+	advancement := int(math.Round(b.ratio() * float64(width)))
+
+	return strings.Repeat(b.barFull, advancement) + b.barProgress + strings.Repeat(b.barEmpty, width-advancement)
+}
+
+// buildBouncingBar draws a block of full characters that goes back and forth in the bar
+func (b *ProgressBar) buildBouncingBar(elapsed time.Duration, width int) string {
+	slots := width + utf8.RuneCountInString(b.barProgress)
+	blockLength := 3
+	if slots <= blockLength {
+		return strings.Repeat(b.barFull, slots)
+	}
+
+	period := 2 * (slots - blockLength)
+	position := int(elapsed/progressRefreshInterval) % period
+	if position > slots-blockLength {
+		position = period - position
+	}
+
+	return strings.Repeat(b.barEmpty, position) + strings.Repeat(b.barFull, blockLength) + strings.Repeat(b.barEmpty, slots-blockLength-position)
+}
+
+// formatDuration displays a duration rounded to the second, like "1m05s"
+func formatDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	if duration < time.Minute {
+		return fmt.Sprintf("%ds", int(duration.Seconds()))
+	}
+	if duration < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(duration.Minutes()), int(duration.Seconds())%60)
+	}
+
+	return fmt.Sprintf("%dh%02dm", int(duration.Hours()), int(duration.Minutes())%60)
+}
+
+// formatBytes displays a size in bytes with a binary unit, like "1.5 MiB"
+func formatBytes(size uint64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	value := float64(size) / 1024
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
@@ -0,0 +1,109 @@
+package styledconsole
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProgressBarFormats checks the preset formats of a progress bar
+func TestProgressBarFormats(t *testing.T) {
+	assert := assert.New(t)
+	bar := New(strings.NewReader(""), &bytes.Buffer{}).NewProgressBar(10)
+
+	assert.Equal("  0 /10 [>-------------------]   0%", bar.render(0))
+	bar.done = 5
+	assert.Equal("  5 /10 [==========>---------]  50%", bar.render(0))
+	bar.done = 10
+	assert.Equal("  10/10 [===================>] 100%", bar.render(0))
+
+	bar.done = 5
+	bar.SetFormat(ProgressFormatVerbose)
+	assert.Equal("  5 /10 [==========>---------]  50%    30s", bar.render(30*time.Second))
+	bar.SetFormat(ProgressFormatVeryVerbose)
+	assert.Equal("  5 /10 [==========>---------]  50%  1m05s/2m10s ", bar.render(65*time.Second))
+	bar.SetFormat(ProgressFormatDebug)
+	assert.Regexp(`^  5 /10 \[==========>---------\]  50%  1m05s/2m10s  [0-9.]+ [KMG]?i?B$`, bar.render(65*time.Second))
+}
+
+// TestProgressBarPlaceholders checks the placeholders of a custom format
+func TestProgressBarPlaceholders(t *testing.T) {
+	assert := assert.New(t)
+	bar := New(strings.NewReader(""), &bytes.Buffer{}).NewProgressBar(200)
+
+	bar.SetFormat("%message:-8s%|%current%/%max%|%percent%%|%remaining%|%estimated%|%rate%|%unknown%")
+	bar.SetMessage("copying")
+	assert.Equal("copying |0  /200|0%|--|--|--|%unknown%", bar.render(0))
+
+	bar.done = 50
+	assert.Equal("copying |50 /200|25%|30s|40s|5.0/s|%unknown%", bar.render(10*time.Second))
+	assert.Equal("copying |50 /200|25%|3h00m|4h00m|0.0/s|%unknown%", bar.render(time.Hour))
+
+	bar.SetFormat("[%bar%]")
+	bar.SetBarCharacters("#", "", ".")
+	bar.SetBarWidth(8)
+	assert.Equal("[##......]", bar.render(0))
+}
+
+// TestFormatBytes checks the sizes are displayed with a readable unit
+func TestFormatBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0 B", formatBytes(0))
+	assert.Equal("1023 B", formatBytes(1023))
+	assert.Equal("1.5 KiB", formatBytes(1536))
+	assert.Equal("3.0 MiB", formatBytes(3*1024*1024))
+	assert.Equal("2.0 GiB", formatBytes(2*1024*1024*1024))
+}
+
+// TestIndeterminateProgressBar checks a bar without a total amount of steps displays a bouncing block
+func TestIndeterminateProgressBar(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	bar := New(strings.NewReader(""), out).NewProgressBar(0)
+
+	bar.Advance(12)
+	assert.Equal("  12 [===-----------------]", bar.render(0))
+	assert.Equal("  12 [--===---------------]", bar.render(2*progressRefreshInterval))
+	assert.Equal("  12 [-----------------===]", bar.render(17*progressRefreshInterval))
+	assert.Equal("  12 [----------------===-]", bar.render(18*progressRefreshInterval))
+	assert.Equal("  12 [===-----------------]", bar.render(34*progressRefreshInterval))
+
+	bar.SetFormat(ProgressFormatVeryVerbose)
+	assert.Equal("  12 [--===---------------]    10s", bar.render(10*time.Second))
+	bar.SetFormat("%current%/%max% %remaining%")
+	assert.Equal("12/? ?", bar.render(10*time.Second))
+
+	// The placeholders that need the total are unknown, instead of showing a full bar
+	bar.SetFormat("%max_bytes% [%bar%] %percent%% %estimated%")
+	bar.SetBarWidth(4)
+	assert.Equal("? [===--] ?% ?", bar.render(0))
+
+	bar.SetFormat("%current%/%max% %remaining%")
+	bar.Finish()
+	assert.Equal("  0 [===-----------------]\n12/? ?\n", out.String())
+}
+
+// TestProgressBarFitsInTerminal checks the bar is sized from the width of the terminal, and the message is truncated to fit
+func TestProgressBarFitsInTerminal(t *testing.T) {
+	assert := assert.New(t)
+	bar := New(strings.NewReader(""), &bytes.Buffer{}, WithTerminal(true), WithSize(30, 20)).NewProgressBar(10)
+	bar.done = 5
+
+	assert.Equal("  5 /10 [=======>-------]  50%", bar.fit(0, 30))
+	assert.Equal("  5 /10 [===>--]  50", bar.fit(0, 20))
+	assert.Equal("  5 /10 [=========================>-------------------------]  50%", bar.fit(0, 200))
+
+	bar.SetBarWidth(10)
+	assert.Equal("  5 /10 [=====>-----]  50%", bar.fit(0, 200))
+	assert.Equal("  5 /10 [====>----]  50%", bar.fit(0, 24))
+
+	bar.SetFormat("%bar% %message%")
+	bar.SetMessage("Downloading the packages")
+	assert.Equal("===>-- Downloading the packag…", bar.fit(0, 30))
+	assert.Equal("===>-- Do…", bar.fit(0, 10))
+	assert.Equal("===>", bar.fit(0, 4))
+}
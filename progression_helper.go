@@ -2,92 +2,453 @@ package styledconsole
 
 import (
 	"fmt"
-	"math"
 	"os"
-	"strconv"
+	"os/signal"
 	"strings"
+	"sync"
 	"time"
+)
+
+var (
+	// progressBarLength is the width of the bars when the output is not a terminal
+	progressBarLength int = 19
+	// progressBarMinLength and progressBarMaxLength are the limits of the width of the bars in a terminal
+	progressBarMinLength int = 5
+	progressBarMaxLength int = 50
+)
 
-	"golang.org/x/term"
+const (
+	// progressRefreshInterval is the minimum delay between two refreshes in a terminal, to avoid stdout-induced lag
+	progressRefreshInterval = 100 * time.Millisecond
+	// progressLogInterval is the minimum delay between two lines of an indeterminate bar, when the output is not a terminal
+	progressLogInterval = time.Second
 )
 
-var progressBarLength int = 19
-var progressStarted bool = false
-var progressTotalSteps int
-var progressDone int
-var lastPrintAdvancement int
-var lastPrintTime time.Time
+// ProgressBar displays the advancement of a task. When the total amount of steps is 0, the bar is indeterminate:
+// it displays an animation and the amount of steps that are done. It is safe for concurrent use.
+type ProgressBar struct {
+	// mutex is shared by all the bars of a MultiProgress, as they are displayed together
+	mutex   *sync.Mutex
+	console *Console
+	multi   *MultiProgress
+
+	total    int64
+	done     int64
+	finished bool
+	message  string
+	stop     chan struct{}
+
+	format      string
+	barWidth    int
+	barFull     string
+	barProgress string
+	barEmpty    string
+
+	startTime            time.Time
+	lastPrintAdvancement int64
+	lastPrintTime        time.Time
+}
+
+// NewProgressBar displays a new progress bar with a given amount of steps, or an indeterminate bar if total is 0.
+// In a terminal, the bar is redrawn periodically until it is finished or cleared.
+func (c *Console) NewProgressBar(total int) *ProgressBar {
+	return c.NewProgressBarWithFormat(total, ProgressFormatNormal)
+}
+
+// NewProgressBarWithFormat displays a new progress bar like NewProgressBar, with the given format (see ProgressBar.SetFormat())
+func (c *Console) NewProgressBarWithFormat(total int, format string) *ProgressBar {
+	return c.startProgressBar(int64(total), format)
+}
+
+// NewBytesProgressBar displays a new progress bar that follows a transfer of total bytes, with the ProgressFormatBytes format.
+// The total is an int64 like the sizes of files, a negative or zero total displays an indeterminate bar.
+func (c *Console) NewBytesProgressBar(total int64) *ProgressBar {
+	return c.startProgressBar(total, ProgressFormatBytes)
+}
+
+// startProgressBar displays a new progress bar and starts redrawing it
+func (c *Console) startProgressBar(total int64, format string) *ProgressBar {
+	if total < 0 {
+		total = 0
+	}
+
+	bar := newProgressBar(c, &sync.Mutex{}, nil, total, format)
+	if c.isTerm() {
+		c.addLiveWidget(bar)
+	}
+
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+	bar.redraw(true)
+
+	if c.isTerm() {
+		bar.stop = make(chan struct{})
+		go bar.animate(bar.stop)
+	}
+
+	return bar
+}
+
+// newProgressBar instanciates a bar with the default characters
+func newProgressBar(c *Console, mutex *sync.Mutex, multi *MultiProgress, total int64, format string) *ProgressBar {
+	return &ProgressBar{
+		mutex:       mutex,
+		console:     c,
+		multi:       multi,
+		total:       total,
+		format:      format,
+		barFull:     "=",
+		barProgress: ">",
+		barEmpty:    "-",
+		startTime:   time.Now(),
+	}
+}
+
+// Advance advances the bar of a given amount of steps. The bar is finished when all the steps are done.
+func (b *ProgressBar) Advance(steps int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.update(b.done + int64(steps))
+}
+
+// SetProgress sets the amount of steps that are done. The bar is finished when all the steps are done.
+func (b *ProgressBar) SetProgress(done int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.update(int64(done))
+}
+
+// AdvanceBy is the same as Advance with an int64 amount of steps, for the bars that follow sizes over 2 GiB on 32-bit platforms
+func (b *ProgressBar) AdvanceBy(steps int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.update(b.done + steps)
+}
+
+// SetProgress64 is the same as SetProgress with an int64 amount of steps, for the bars that follow sizes over 2 GiB on 32-bit platforms
+func (b *ProgressBar) SetProgress64(done int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
 
-// ProgressStart starts a progress bar of a given duration
-func ProgressStart(totalSteps int) {
-	if progressStarted || totalSteps < 0 {
+	b.update(done)
+}
+
+// Finish completes all the steps of the bar, and stops updating it
+func (b *ProgressBar) Finish() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.finished {
+		b.finish()
+	}
+}
+
+// Clear stops the bar and removes it from the terminal
+func (b *ProgressBar) Clear() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	wasFinished := b.finished
+	b.stopAnimation()
+
+	if !b.console.isTerm() {
+		// The lines that were printed cannot be removed
+		return
+	}
+
+	if b.multi != nil {
+		b.multi.remove(b)
 		return
 	}
 
-	fmt.Printf("  %s", buildProgressBar(0, totalSteps))
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
-		fmt.Print("\n")
+	if wasFinished {
+		// The bar was followed by a line break
+		fmt.Fprint(b.console.out, "\033[1A")
 	}
+	fmt.Fprint(b.console.out, "\033[1000D\033[0K")
+}
 
-	progressStarted = true
-	progressTotalSteps = totalSteps
-	progressDone = 0
-	lastPrintAdvancement = 0
-	lastPrintTime = time.Now()
+// isFinished returns whether the bar is stopped
+func (b *ProgressBar) isFinished() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.finished
 }
 
-// ProgressAdvance advances the current progress bar of a given stepCount. If there is no progressBar it does nothing
-func ProgressAdvance(stepCount int) {
-	if !progressStarted || stepCount == 0 {
+// update sets the amount of steps that are done and refreshes the bar, the mutex must be held
+func (b *ProgressBar) update(done int64) {
+	if b.finished {
 		return
 	}
 
-	if progressDone+stepCount < progressTotalSteps {
-		progressDone += stepCount
-		if term.IsTerminal(int(os.Stdout.Fd())) {
-			if time.Since(lastPrintTime) > 1e8 {
-				// Do not refresh faster than 10fps, to avoid stdout-induced lag
-				fmt.Printf("\033[1000D  %s", buildProgressBar(progressDone, progressTotalSteps))
-				lastPrintTime = time.Now()
+	if b.total > 0 && done >= b.total {
+		b.finish()
+		return
+	}
+	if done < 0 {
+		done = 0
+	}
+	if done == b.done {
+		return
+	}
+
+	b.done = done
+	b.redraw(false)
+}
+
+// finish completes the bar, the mutex must be held
+func (b *ProgressBar) finish() {
+	if b.total > 0 {
+		b.done = b.total
+	}
+	b.stopAnimation()
+	b.redraw(true)
+
+	if b.multi == nil && b.console.isTerm() {
+		fmt.Fprint(b.console.out, "\n")
+	}
+}
+
+// stopAnimation marks the bar as finished and stops redrawing it periodically, the mutex must be held
+func (b *ProgressBar) stopAnimation() {
+	b.finished = true
+	if b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+}
+
+// animate redraws the bar periodically until it is stopped, so that the times and the indeterminate bar stay up to date.
+// The bar is also redrawn as soon as the terminal is resized.
+func (b *ProgressBar) animate(stop <-chan struct{}) {
+	ticker := time.NewTicker(progressRefreshInterval)
+	defer ticker.Stop()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-resized:
+			b.mutex.Lock()
+			if !b.finished {
+				b.redraw(true)
+			}
+			b.mutex.Unlock()
+		case <-ticker.C:
+			b.mutex.Lock()
+			if !b.finished {
+				b.redraw(true)
 			}
-		} else if (progressDone - lastPrintAdvancement) >= int(float64(progressTotalSteps)*0.05) {
-			fmt.Printf("  %s\n", buildProgressBar(progressDone, progressTotalSteps))
-			lastPrintAdvancement = progressDone
+			b.mutex.Unlock()
 		}
-	} else {
-		progressDone = progressTotalSteps
-		ProgressFinish()
 	}
 }
 
-// ProgressFinish finishes the current progress bar. If there is no progressBar it does nothing
-func ProgressFinish() {
-	if !progressStarted {
+// redraw prints the current state of the bar, unless it was printed too recently. The mutex must be held.
+func (b *ProgressBar) redraw(force bool) {
+	c := b.console
+	if !c.isTerm() {
+		// Without a terminal, a new line is printed every 5%, or every second for an indeterminate bar
+		if b.total == 0 {
+			force = force || (b.done != b.lastPrintAdvancement && time.Since(b.lastPrintTime) >= progressLogInterval)
+		} else {
+			force = force || (b.done-b.lastPrintAdvancement) >= int64(float64(b.total)*0.05)
+		}
+
+		if force {
+			fmt.Fprintf(c.out, "%s\n", b.line())
+			b.lastPrintAdvancement = b.done
+			b.lastPrintTime = time.Now()
+		}
+		return
+	}
+
+	if b.multi != nil {
+		b.multi.redraw(force)
 		return
 	}
 
-	if term.IsTerminal(int(os.Stdout.Fd())) {
-		fmt.Printf("\033[1000D  %s\n", buildProgressBar(progressTotalSteps, progressTotalSteps))
-	} else {
-		fmt.Printf("  %s\n", buildProgressBar(progressTotalSteps, progressTotalSteps))
+	if force || time.Since(b.lastPrintTime) > progressRefreshInterval {
+		fmt.Fprintf(c.out, "\033[1000D%s\033[0K", b.line())
+		b.lastPrintTime = time.Now()
 	}
+}
+
+// line renders the bar, so that it fits on a single row of the terminal. The mutex must be held.
+func (b *ProgressBar) line() string {
+	if !b.console.isTerm() {
+		return b.render(time.Since(b.startTime))
+	}
+
+	// Writing on the last column would wrap the line on some terminals
+	width, _ := b.console.getWinsize()
+	return b.fit(time.Since(b.startTime), width-1)
+}
+
+// MultiProgress displays several progress bars stacked on top of each other and updated in place.
+// It is safe for concurrent use.
+type MultiProgress struct {
+	mutex   sync.Mutex
+	console *Console
+	bars    []*ProgressBar
 
-	progressStarted = false
+	drawnLines    int
+	lastPrintTime time.Time
+	animating     bool
 }
 
-func buildProgressBar(done int, total int) string {
-	advancementRatio := float64(done) / float64(total)
-	advancement := int(math.Round(advancementRatio * float64(progressBarLength)))
+// NewMultiProgress instanciates an empty container of progress bars
+func (c *Console) NewMultiProgress() *MultiProgress {
+	return &MultiProgress{console: c}
+}
+
+// NewProgressBar adds a new progress bar with a given amount of steps below the other bars of the container
+func (m *MultiProgress) NewProgressBar(total int) *ProgressBar {
+	return m.NewProgressBarWithFormat(total, ProgressFormatNormal)
+}
+
+// NewProgressBarWithFormat adds a new progress bar like NewProgressBar, with the given format (see ProgressBar.SetFormat())
+func (m *MultiProgress) NewProgressBarWithFormat(total int, format string) *ProgressBar {
+	return m.addProgressBar(int64(total), format)
+}
+
+// NewBytesProgressBar adds a new progress bar that follows a transfer of total bytes, like Console.NewBytesProgressBar()
+func (m *MultiProgress) NewBytesProgressBar(total int64) *ProgressBar {
+	return m.addProgressBar(total, ProgressFormatBytes)
+}
 
-	maxDigits := int(math.Ceil(math.Log10(float64(total + 1))))
-	paddedDone := fmt.Sprintf("%-"+strconv.Itoa(maxDigits)+"d", done)
+// addProgressBar adds a new progress bar below the other bars of the container
+func (m *MultiProgress) addProgressBar(total int64, format string) *ProgressBar {
+	if total < 0 {
+		total = 0
+	}
+	if m.console.isTerm() {
+		m.console.addLiveWidget(m)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	bar := newProgressBar(m.console, &m.mutex, m, total, format)
+	m.bars = append(m.bars, bar)
+	bar.redraw(true)
+
+	if m.console.isTerm() && !m.animating {
+		m.animating = true
+		go m.animate()
+	}
+
+	return bar
+}
+
+// remove removes a bar from the container and from the terminal, the mutex must be held
+func (m *MultiProgress) remove(bar *ProgressBar) {
+	for i, b := range m.bars {
+		if b == bar {
+			m.bars = append(m.bars[:i], m.bars[i+1:]...)
+			break
+		}
+	}
+
+	m.redraw(true)
+}
+
+// animate redraws the bars periodically and when the terminal is resized, until they are all finished
+func (m *MultiProgress) animate() {
+	ticker := time.NewTicker(progressRefreshInterval)
+	defer ticker.Stop()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-resized:
+		}
+
+		m.mutex.Lock()
+		running := false
+		for _, bar := range m.bars {
+			running = running || !bar.finished
+		}
+
+		if !running {
+			m.animating = false
+			m.mutex.Unlock()
+			return
+		}
+
+		m.redraw(true)
+		m.mutex.Unlock()
+	}
+}
+
+// redraw prints all the bars over the ones that were printed before, unless they were printed too recently.
+// The mutex must be held.
+func (m *MultiProgress) redraw(force bool) {
+	if !force && time.Since(m.lastPrintTime) <= progressRefreshInterval {
+		return
+	}
+
+	var output strings.Builder
+	if m.drawnLines > 0 {
+		fmt.Fprintf(&output, "\033[%dA", m.drawnLines)
+	}
+	for _, bar := range m.bars {
+		fmt.Fprintf(&output, "\033[1000D%s\033[0K\n", bar.line())
+	}
+	if len(m.bars) < m.drawnLines {
+		output.WriteString("\033[0J")
+	}
+
+	fmt.Fprint(m.console.out, output.String())
+	m.drawnLines = len(m.bars)
+	m.lastPrintTime = time.Now()
+}
+
+// ProgressStart starts a progress bar of a given duration, or an indeterminate bar if totalSteps is 0
+func (c *Console) ProgressStart(totalSteps int) {
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	if (c.progressBar != nil && !c.progressBar.isFinished()) || totalSteps < 0 {
+		return
+	}
+
+	c.progressBar = c.NewProgressBar(totalSteps)
+}
+
+// ProgressAdvance advances the current progress bar of a given stepCount. If there is no progressBar it does nothing
+func (c *Console) ProgressAdvance(stepCount int) {
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	if c.progressBar == nil || stepCount == 0 {
+		return
+	}
+
+	c.progressBar.Advance(stepCount)
+}
+
+// ProgressFinish finishes the current progress bar. If there is no progressBar it does nothing
+func (c *Console) ProgressFinish() {
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	if c.progressBar == nil {
+		return
+	}
 
-	return fmt.Sprintf(
-		"%s/%d [%s>%s] %3d%%",
-		paddedDone,
-		total,
-		strings.Repeat("=", advancement),
-		strings.Repeat("-", progressBarLength-advancement),
-		int(advancementRatio*100),
-	)
+	c.progressBar.Finish()
+	c.progressBar = nil
 }
@@ -0,0 +1,102 @@
+package styledconsole
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProgressBarWithoutTerminal checks a progress bar prints a new line every 5% when the output is not a terminal
+func TestProgressBarWithoutTerminal(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out)
+
+	bar := console.NewProgressBar(4)
+	bar.Advance(1)
+	bar.SetProgress(3)
+	bar.Advance(0)
+	bar.Advance(5)
+	bar.Advance(1)
+
+	assert.Equal(
+		"  0/4 [>-------------------]   0%\n"+
+			"  1/4 [=====>--------------]  25%\n"+
+			"  3/4 [==============>-----]  75%\n"+
+			"  4/4 [===================>] 100%\n",
+		out.String(),
+	)
+}
+
+// TestProgressBarInTerminal checks a progress bar is updated in place in a terminal
+func TestProgressBarInTerminal(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithSize(34, 20))
+
+	bar := console.NewProgressBar(2)
+	bar.Finish()
+	bar.Advance(1)
+	assert.Equal(
+		"\033[1000D  0/2 [>-------------------]   0%\033[0K\033[1000D  2/2 [===================>] 100%\033[0K\n",
+		out.String(),
+	)
+
+	out.Reset()
+	bar = console.NewProgressBar(2)
+	bar.Clear()
+	assert.Equal("\033[1000D  0/2 [>-------------------]   0%\033[0K\033[1000D\033[0K", out.String())
+}
+
+// TestMultiProgress checks several bars are stacked and redrawn in place
+func TestMultiProgress(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithSize(34, 20))
+
+	multi := console.NewMultiProgress()
+	first := multi.NewProgressBar(2)
+	second := multi.NewProgressBar(2)
+	first.Finish()
+	second.Clear()
+
+	assert.Equal(
+		"\033[1000D  0/2 [>-------------------]   0%\033[0K\n"+
+			"\033[1A\033[1000D  0/2 [>-------------------]   0%\033[0K\n\033[1000D  0/2 [>-------------------]   0%\033[0K\n"+
+			"\033[2A\033[1000D  2/2 [===================>] 100%\033[0K\n\033[1000D  0/2 [>-------------------]   0%\033[0K\n"+
+			"\033[2A\033[1000D  2/2 [===================>] 100%\033[0K\n\033[0J",
+		out.String(),
+	)
+}
+
+// TestProgressBarConcurrency checks the bars can be advanced from several goroutines
+func TestProgressBarConcurrency(t *testing.T) {
+	assert := assert.New(t)
+	console := New(strings.NewReader(""), &bytes.Buffer{}, WithTerminal(true))
+	otherConsole := New(strings.NewReader(""), &bytes.Buffer{}, WithTerminal(true))
+
+	multi := console.NewMultiProgress()
+	bars := []*ProgressBar{multi.NewProgressBar(100), multi.NewProgressBar(100)}
+	single := otherConsole.NewProgressBar(200)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(bar *ProgressBar) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				bar.Advance(1)
+				single.Advance(1)
+			}
+		}(bars[i%2])
+	}
+	wg.Wait()
+
+	for _, bar := range append(bars, single) {
+		assert.True(bar.isFinished())
+		assert.Equal(bar.total, bar.done)
+	}
+}
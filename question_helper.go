@@ -1,15 +1,14 @@
 package styledconsole
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strings"
-	"syscall"
+	"unicode"
 
-	"golang.org/x/term"
+	"github.com/corentindeboisset/styledconsole/styledprinter"
 )
 
 type question struct {
@@ -20,11 +19,49 @@ type question struct {
 	DefaultChoice int
 	DefaultAnswer string
 	Validator     func(string) bool
+	Completer     func(string) []string
 }
 
-func askQuestion(q question) (string, error) {
+// askQuestion prompts the question until a valid answer is given. When the answer timeout of the console is over,
+// the default answer is returned.
+func (c *Console) askQuestion(ctx context.Context, q question) (string, error) {
+	promptCtx, cancel := c.promptContext(ctx)
+	defer cancel()
+
+	ret, err := c.askUntilValid(promptCtx, q)
+	if err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if isAnswerTimeout(ctx, err) {
+		if answer, ok := q.fallbackAnswer(); ok {
+			return answer, nil
+		}
+		return "", ErrTimeout
+	}
+	if err == nil && !q.IsClosed && !q.IsHidden {
+		// Only the valid answers are kept, the rejected ones are not worth recalling
+		c.addToHistory(ret)
+	}
+
+	return ret, c.handleInterrupt(err)
+}
+
+// fallbackAnswer returns the default answer of the question, if there is one
+func (q question) fallbackAnswer() (string, bool) {
+	if q.IsClosed {
+		if q.DefaultChoice >= 0 && q.DefaultChoice < len(q.Choices) {
+			return q.Choices[q.DefaultChoice], true
+		}
+		return "", false
+	}
+
+	return q.DefaultAnswer, q.DefaultAnswer != ""
+}
+
+// askUntilValid prompts the question again until the validator accepts the answer
+func (c *Console) askUntilValid(ctx context.Context, q question) (string, error) {
 	if q.IsClosed && len(q.Choices) > 1 {
-		ret, err := askClosedQuestion(q)
+		ret, err := c.askClosedQuestion(ctx, q)
 		if err != nil {
 			return "", err
 		}
@@ -35,9 +72,9 @@ func askQuestion(q question) (string, error) {
 		var err error
 		for {
 			if q.IsHidden {
-				ret, err = askHiddenQuestion(q)
+				ret, err = c.askHiddenQuestion(ctx, q)
 			} else {
-				ret, err = askRegularQuestion(q)
+				ret, err = c.askRegularQuestion(ctx, q)
 			}
 
 			if err != nil {
@@ -52,7 +89,7 @@ func askQuestion(q question) (string, error) {
 			if q.Validator == nil || q.Validator(ret) {
 				return ret, nil
 			} else {
-				fmt.Printf("%s\n", redStyle.Apply("This answer is invalid."))
+				fmt.Fprintf(c.out, "%s\n", c.applyStyle(redStyle, "This answer is invalid."))
 			}
 		}
 	}
@@ -62,176 +99,447 @@ func askQuestion(q question) (string, error) {
 	return "", errors.New("the question object is invalid")
 }
 
-func askClosedQuestion(q question) (string, error) {
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+func (c *Console) askClosedQuestion(ctx context.Context, q question) (string, error) {
+	if !c.isTerm() {
 		return "", errors.New("cannot open an interacive prompt outside of a TTY")
 	}
 
-	width, height := getWinsize()
-
-	oldState, err := term.MakeRaw(int(os.Stdout.Fd()))
+	restoreTerminal, err := c.makeRaw()
 	if err != nil {
 		return "", fmt.Errorf("there was an error switching terminal to raw mode: %w", err)
 	}
+	defer restoreTerminal()
+	disableMouse := c.enableMouse()
+	defer disableMouse()
 
-	if height < 3 || width < 20 {
-		for {
-			fmt.Print("Terminal is too small... Resize and press a key.\n")
-			key, err := getKey()
-			if err != nil {
-				return "", fmt.Errorf("there was an error reading a character from stdin: %w", err)
-			}
-
-			if key.KeyType == "EOF" {
-				// We skip and move on to the next step
-				break
-			}
-
-			width, height = getWinsize()
-		}
+	width, height, err := c.waitForTerminalSize(ctx)
+	if err != nil {
+		return "", err
 	}
 
 	// Prepare the list of printable options
 	printableChoices := []string{}
 	for _, choice := range q.Choices {
-		if len(choice) < width-3 {
-			printableChoices = append(printableChoices, choice)
-		} else {
-			printableChoices = append(printableChoices, fmt.Sprintf("%s…", choice[:width-5]))
-		}
+		printableChoices = append(printableChoices, styledprinter.Truncate(choice, width-4, "…"))
 	}
 
 	// Run the display loop
 	selectedIndex := -1
 	choiceCount := len(printableChoices)
-	scrollWindowHeight := getScrollWindowHeight(choiceCount, height)
 	highlightedIndex := 0
-	scroll := 0
-
 	if q.DefaultChoice >= 0 && q.DefaultChoice < choiceCount-1 {
-		// Set the default answer, and adapt the initial scrolling
+		// Set the default answer, the initial scrolling is adapted to it
 		highlightedIndex = q.DefaultChoice
-		if highlightedIndex > scrollWindowHeight {
-			if highlightedIndex < choiceCount-2 {
-				scroll = highlightedIndex - scrollWindowHeight
-			} else {
-				scroll = choiceCount - scrollWindowHeight - 2
-			}
-		}
 	}
 
-	hideCursor()
-	for selectedIndex == -1 {
-		clearWindowFromCursor()
-		fmt.Printf("%s:", greenStyle.Apply(q.Label))
-
-		// Print the first line, either the first choice or a "↑"
-		if scroll > 0 {
-			fmt.Print("\n\033[1000D   ↑")
-		} else {
-			fmt.Print(formatClosedQuestionChoice(printableChoices[0], highlightedIndex == 0))
+	// The list only contains the choices that match the filter typed by the user
+	filter := []rune{}
+	matches := fuzzyFilter(q.Choices, ``)
+	list := newChoiceList(printableChoices, height, highlightedIndex)
+	applyFilter := func() {
+		matches = fuzzyFilter(q.Choices, string(filter))
+		filteredChoices := []string{}
+		for _, match := range matches {
+			filteredChoices = append(filteredChoices, printableChoices[match.index])
 		}
+		list = newChoiceList(filteredChoices, height, 0)
+		list.filter = string(filter)
+	}
 
-		// Print some choices
-		for i := scroll + 1; i <= scroll+scrollWindowHeight; i++ {
-			fmt.Print(formatClosedQuestionChoice(printableChoices[i], highlightedIndex == i))
+	formatChoice := func(index int, highlighted bool) string {
+		match := matches[index]
+		label := printableChoices[match.index]
+		if highlighted {
+			return c.formatClosedQuestionChoice(label, highlighted)
 		}
 
-		// Print the last line, either the last choice or a "↓"
-		if scroll < choiceCount-scrollWindowHeight-2 {
-			fmt.Print("\n\033[1000D   ↓")
-		} else {
-			fmt.Print(formatClosedQuestionChoice(printableChoices[choiceCount-1], highlightedIndex == choiceCount-1))
+		// The truncated part of the choice cannot be highlighted
+		visibleRunes := len([]rune(label))
+		if label != q.Choices[match.index] {
+			visibleRunes--
+		}
+		positions := []int{}
+		for _, position := range match.positions {
+			if position < visibleRunes {
+				positions = append(positions, position)
+			}
 		}
 
-		// Put the cursor back at the beginning
-		fmt.Printf("\033[%dA\033[1000D", scrollWindowHeight+2)
+		return fmt.Sprintf("\n\033[1000D   %s", c.highlightMatches(label, positions))
+	}
+
+	// The row of the label is needed to find the clicked choice, it is asked to the terminal after every redraw
+	labelRow := 0
+
+	// The list can be resized or filtered after it is drawn, the cursor is put back below the lines that are on the screen
+	drawnLines := 0
+	c.hideCursor()
+	defer func() {
+		// Whatever the answer, the cursor is put back below the list
+		fmt.Fprintf(c.out, "\033[%dB\033[1000D", drawnLines+1)
+		c.showCursor()
+	}()
+	for selectedIndex == -1 {
+		drawnLines = c.printChoiceList(q.Label, list, ``, formatChoice)
+		if c.mouse {
+			_ = c.keys.RequestCursorPosition(c.out)
+		}
 
 		for {
-			typedKey, err := getKey()
+			key, err := c.keys.ReadKeyContext(ctx)
 
 			// Re-parse the height in case the user resized their terminal
-			_, height = getWinsize()
-			scrollWindowHeight = getScrollWindowHeight(choiceCount, height)
+			_, height = c.getWinsize()
+			list.resize(height)
 
-			if err != nil || typedKey == nil {
-				return "", fmt.Errorf("there was an error reading user input: %w", err)
-			}
-			if typedKey.KeyType == "EOF" {
+			if err == io.EOF {
 				if q.DefaultChoice >= 0 && q.DefaultChoice < choiceCount-1 {
 					return q.Choices[q.DefaultChoice], nil
 				}
 
 				return "", errors.New("error parsing user activity from Stdin (EOF)")
 			}
+			if err != nil {
+				return "", fmt.Errorf("there was an error reading user input: %w", err)
+			}
 
-			if typedKey.KeyType == "arrowKey" && typedKey.ArrowKey == '↑' {
-				// Up
-				if highlightedIndex == 0 {
-					highlightedIndex = choiceCount - 1
-					scroll = choiceCount - scrollWindowHeight - 2 // scroll to the bottom
-				} else {
-					highlightedIndex -= 1
-					// Update scrolling if necessary
-					if scroll >= highlightedIndex {
-						if highlightedIndex > 1 {
-							scroll = highlightedIndex - 1
-						} else {
-							scroll = 0
-						}
-					}
+			if key.Key == KeyUp {
+				list.moveUp()
+				break
+			} else if key.Key == KeyDown {
+				list.moveDown()
+				break
+			} else if key.Key == KeyPageUp {
+				list.moveTo(list.highlightedIndex - list.windowHeight)
+				break
+			} else if key.Key == KeyPageDown {
+				list.moveTo(list.highlightedIndex + list.windowHeight)
+				break
+			} else if key.Key == KeyHome {
+				list.moveTo(0)
+				break
+			} else if key.Key == KeyEnd {
+				list.moveTo(len(list.choices) - 1)
+				break
+			} else if key.Key == KeyEnter || (key.Key == KeyRune && key.Rune == ' ' && key.Modifiers == 0 && len(filter) == 0) {
+				if len(list.choices) == 0 {
+					// Nothing matches the filter
+					continue
 				}
+				selectedIndex = matches[list.highlightedIndex].index
 				break
-			} else if typedKey.KeyType == "arrowKey" && typedKey.ArrowKey == '↓' {
-				// Down
-				if highlightedIndex == choiceCount-1 {
-					highlightedIndex = 0
-					scroll = 0 // scroll to the top
-				} else {
-					highlightedIndex += 1
-					// Update scrolling if necessary
-					if scroll <= highlightedIndex-scrollWindowHeight-1 {
-						if highlightedIndex < choiceCount-2 {
-							scroll = highlightedIndex - scrollWindowHeight
-						} else {
-							scroll = choiceCount - scrollWindowHeight - 2
-						}
-					}
+			} else if key.Key == KeyBackspace {
+				if len(filter) > 0 {
+					filter = filter[:len(filter)-1]
+					applyFilter()
+					break
+				}
+			} else if key.isCtrl('u') {
+				if len(filter) > 0 {
+					filter = filter[:0]
+					applyFilter()
+					break
 				}
+			} else if key.isCharacter() && unicode.IsPrint(key.Rune) {
+				filter = append(filter, key.Rune)
+				applyFilter()
 				break
-			} else if typedKey.KeyType == "char" && (typedKey.Character == '\r' || typedKey.Character == '\n' || typedKey.Character == ' ') {
-				selectedIndex = highlightedIndex
+			} else if key.Key == KeyPaste {
+				// The pasted text is added to the filter without its line breaks, that cannot select a choice
+				filter = append(filter, key.pastedRunes(``)...)
+				applyFilter()
 				break
-			} else if typedKey.KeyType == "char" && typedKey.Character == 3 {
-				// Ctrl-C
-				showCursor()
-				fmt.Printf("\033[%dB\033[1000D", scrollWindowHeight+3)
-				_ = term.Restore(int(os.Stdout.Fd()), oldState)
-				_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+			} else if key.Key == KeyCursorPosition {
+				labelRow = key.Y
+			} else if key.Key == KeyMouse && (key.Button == MouseWheelUp || key.Button == MouseWheelDown) {
+				list.scrollBy(key.Button)
+				break
+			} else if key.Key == KeyMouse && key.Button == MouseLeft && labelRow > 0 {
+				if index := list.click(key.Y - labelRow - 1); index >= 0 {
+					selectedIndex = matches[index].index
+				}
+				break
+			} else if key.isCtrl('c') {
+				return "", ErrInterrupted
+			} else if key.isCtrl('d') {
+				return "", ErrAborted
 			}
 		}
 	}
-	fmt.Printf("\033[%dB\033[1000D", scrollWindowHeight+3)
-	showCursor()
-	_ = term.Restore(int(os.Stdout.Fd()), oldState)
 
 	return q.Choices[selectedIndex], nil
 }
 
-func askHiddenQuestion(q question) (string, error) {
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+// waitForTerminalSize asks the user to resize the terminal until a list of choices can be displayed, and returns its size.
+// The terminal must be in raw mode.
+func (c *Console) waitForTerminalSize(ctx context.Context) (int, int, error) {
+	width, height := c.getWinsize()
+	if height < 3 || width < 20 {
+		for {
+			fmt.Fprint(c.out, "Terminal is too small... Resize and press a key.\n")
+			_, err := c.keys.ReadKeyContext(ctx)
+			if err == io.EOF {
+				// We skip and move on to the next step
+				break
+			}
+			if err != nil {
+				return 0, 0, fmt.Errorf("there was an error reading a character from stdin: %w", err)
+			}
+
+			width, height = c.getWinsize()
+		}
+	}
+
+	return width, height, nil
+}
+
+// choiceList is a list of choices that scrolls to keep the highlighted choice visible.
+// The first and the last choices are always displayed, or replaced with an arrow when the list is scrolled.
+type choiceList struct {
+	choices          []string
+	filter           string
+	highlightedIndex int
+	scroll           int
+	windowHeight     int
+}
+
+// newChoiceList creates a list that fits in a terminal of the given height, scrolled to the highlighted choice
+func newChoiceList(choices []string, height int, highlightedIndex int) *choiceList {
+	list := &choiceList{choices: choices, highlightedIndex: highlightedIndex}
+	list.resize(height)
+
+	choiceCount := len(choices)
+	if highlightedIndex > list.windowHeight {
+		if highlightedIndex < choiceCount-2 {
+			list.scroll = highlightedIndex - list.windowHeight
+		} else {
+			list.scroll = choiceCount - list.windowHeight - 2
+		}
+	}
+
+	return list
+}
+
+// resize adapts the amount of visible choices to the height of the terminal
+func (l *choiceList) resize(height int) {
+	l.windowHeight = getScrollWindowHeight(len(l.choices), height)
+}
+
+// moveUp highlights the previous choice, or the last one from the top of the list
+func (l *choiceList) moveUp() {
+	choiceCount := len(l.choices)
+	if choiceCount == 0 {
+		return
+	}
+	if l.highlightedIndex == 0 {
+		l.highlightedIndex = choiceCount - 1
+		l.scroll = choiceCount - l.windowHeight - 2 // scroll to the bottom
+		if l.scroll < 0 {
+			l.scroll = 0
+		}
+		return
+	}
+
+	l.highlightedIndex -= 1
+	// Update scrolling if necessary
+	if l.scroll >= l.highlightedIndex {
+		if l.highlightedIndex > 1 {
+			l.scroll = l.highlightedIndex - 1
+		} else {
+			l.scroll = 0
+		}
+	}
+}
+
+// moveDown highlights the next choice, or the first one from the bottom of the list
+func (l *choiceList) moveDown() {
+	choiceCount := len(l.choices)
+	if choiceCount == 0 {
+		return
+	}
+	if l.highlightedIndex == choiceCount-1 {
+		l.highlightedIndex = 0
+		l.scroll = 0 // scroll to the top
+		return
+	}
+
+	l.highlightedIndex += 1
+	// Update scrolling if necessary
+	if l.scroll <= l.highlightedIndex-l.windowHeight-1 {
+		if l.highlightedIndex < choiceCount-2 {
+			l.scroll = l.highlightedIndex - l.windowHeight
+		} else {
+			l.scroll = choiceCount - l.windowHeight - 2
+		}
+	}
+}
+
+// moveTo highlights the choice at the given index, or the closest one, and scrolls the list to display it
+func (l *choiceList) moveTo(index int) {
+	choiceCount := len(l.choices)
+	if choiceCount == 0 {
+		return
+	}
+	if index < 0 {
+		index = 0
+	} else if index > choiceCount-1 {
+		index = choiceCount - 1
+	}
+
+	l.highlightedIndex = index
+	if l.scroll >= index {
+		l.scroll = index - 1
+	} else if l.scroll <= index-l.windowHeight-1 {
+		l.scroll = index - l.windowHeight
+	}
+
+	if maxScroll := choiceCount - l.windowHeight - 2; l.scroll > maxScroll {
+		l.scroll = maxScroll
+	}
+	if l.scroll < 0 {
+		l.scroll = 0
+	}
+}
+
+// scrollBy moves the window of visible choices one line up or down with the mouse wheel,
+// the highlighted choice being moved to stay visible
+func (l *choiceList) scrollBy(wheel MouseButton) {
+	choiceCount := len(l.choices)
+	if choiceCount == 0 {
+		return
+	}
+
+	maxScroll := choiceCount - l.windowHeight - 2
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if wheel == MouseWheelUp && l.scroll > 0 {
+		l.scroll--
+	} else if wheel == MouseWheelDown && l.scroll < maxScroll {
+		l.scroll++
+	}
+
+	firstVisible, lastVisible := l.scroll+1, l.scroll+l.windowHeight
+	if l.scroll == 0 {
+		firstVisible = 0
+	}
+	if l.scroll == maxScroll {
+		lastVisible = choiceCount - 1
+	}
+	if l.highlightedIndex < firstVisible {
+		l.highlightedIndex = firstVisible
+	} else if l.highlightedIndex > lastVisible {
+		l.highlightedIndex = lastVisible
+	}
+}
+
+// click highlights the choice printed on the given line below the label, and returns its index.
+// Clicking on the arrows of a scrolled list scrolls it, and -1 is returned when no choice is clicked.
+func (l *choiceList) click(line int) int {
+	choiceCount := len(l.choices)
+	if choiceCount == 0 || line < 0 || line >= l.lineCount() {
+		return -1
+	}
+
+	index := l.scroll + line
+	if line == 0 {
+		if l.scroll > 0 {
+			l.scrollBy(MouseWheelUp)
+			return -1
+		}
+		index = 0
+	} else if line == l.windowHeight+1 {
+		if l.scroll < choiceCount-l.windowHeight-2 {
+			l.scrollBy(MouseWheelDown)
+			return -1
+		}
+		index = choiceCount - 1
+	}
+
+	l.highlightedIndex = index
+	return index
+}
+
+// lineCount returns the amount of lines printed below the label
+func (l *choiceList) lineCount() int {
+	if len(l.choices) == 0 {
+		// A message replaces the choices
+		return 1
+	}
+
+	return l.windowHeight + 2
+}
+
+// printChoiceList prints the label and the visible choices with formatChoice, followed by an optional footer,
+// and puts the cursor back at the beginning of the label. It returns the amount of lines printed below the label.
+func (c *Console) printChoiceList(label string, list *choiceList, footer string, formatChoice func(index int, highlighted bool) string) int {
+	choiceCount := len(list.choices)
+
+	c.clearWindowFromCursor()
+	fmt.Fprintf(c.out, "%s:", c.applyStyle(greenStyle, label))
+	if list.filter != `` {
+		fmt.Fprintf(c.out, " %s", c.applyStyle(yellowStyle, list.filter))
+	}
+
+	if choiceCount == 0 {
+		fmt.Fprintf(c.out, "\n\033[1000D   %s", c.applyStyle(redStyle, "No choice matches the filter."))
+	} else {
+		c.printVisibleChoices(list, formatChoice)
+	}
+
+	lineCount := list.lineCount()
+	if footer != `` {
+		fmt.Fprintf(c.out, "\n\033[1000D%s", footer)
+		lineCount++
+	}
+
+	// Put the cursor back at the beginning
+	fmt.Fprintf(c.out, "\033[%dA\033[1000D", lineCount)
+
+	return lineCount
+}
+
+// printVisibleChoices prints the choices of the list that fit in its window
+func (c *Console) printVisibleChoices(list *choiceList, formatChoice func(index int, highlighted bool) string) {
+	choiceCount := len(list.choices)
+
+	// Print the first line, either the first choice or a "↑"
+	if list.scroll > 0 {
+		fmt.Fprint(c.out, "\n\033[1000D   ↑")
+	} else {
+		fmt.Fprint(c.out, formatChoice(0, list.highlightedIndex == 0))
+	}
+
+	// Print some choices
+	for i := list.scroll + 1; i <= list.scroll+list.windowHeight; i++ {
+		fmt.Fprint(c.out, formatChoice(i, list.highlightedIndex == i))
+	}
+
+	// Print the last line, either the last choice or a "↓"
+	if list.scroll < choiceCount-list.windowHeight-2 {
+		fmt.Fprint(c.out, "\n\033[1000D   ↓")
+	} else if choiceCount > 1 {
+		fmt.Fprint(c.out, formatChoice(choiceCount-1, list.highlightedIndex == choiceCount-1))
+	}
+}
+
+func (c *Console) askHiddenQuestion(ctx context.Context, q question) (string, error) {
+	if !c.isTerm() {
 		return "", errors.New("cannot open a prompt outside of a terminal")
 	}
 
-	fmt.Printf("\n%s :\n > ", greenStyle.Apply(strings.TrimSpace(q.Label)))
-	answerBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintf(c.out, "\n%s :\n > ", c.applyStyle(greenStyle, strings.TrimSpace(q.Label)))
+	answerBytes, err := c.readPassword(ctx)
 	// The typed line break is hidden so we have to force it
-	fmt.Print("\n")
+	fmt.Fprint(c.out, "\n")
 
 	if err != nil {
 		if err == io.EOF {
 			return string(answerBytes), err
 		}
+		if isLeftPrompt(err) {
+			return "", err
+		}
 
 		return "", fmt.Errorf("there was an error reading the stdin: %w", err)
 	}
@@ -239,36 +547,37 @@ func askHiddenQuestion(q question) (string, error) {
 	return string(answerBytes), nil
 }
 
-func askRegularQuestion(q question) (string, error) {
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+func (c *Console) askRegularQuestion(ctx context.Context, q question) (string, error) {
+	if !c.isTerm() {
 		return "", errors.New("cannot open a prompt outside of a terminal")
 	}
 
 	var prompt string
 	if q.DefaultAnswer != "" {
-		prompt = fmt.Sprintf("\n%s [%s]:\n > ", greenStyle.Apply(strings.TrimSpace(q.Label)), yellowStyle.Apply(q.DefaultAnswer))
+		prompt = fmt.Sprintf("\n%s [%s]:\n", c.applyStyle(greenStyle, strings.TrimSpace(q.Label)), c.applyStyle(yellowStyle, q.DefaultAnswer))
 	} else {
-		prompt = fmt.Sprintf("\n%s :\n > ", greenStyle.Apply(strings.TrimSpace(q.Label)))
+		prompt = fmt.Sprintf("\n%s :\n", c.applyStyle(greenStyle, strings.TrimSpace(q.Label)))
 	}
-	fmt.Print(prompt)
+	fmt.Fprint(c.out, prompt+" > ")
 
-	reader := bufio.NewReader(os.Stdin)
-	answer, err := reader.ReadString('\n')
+	answer, err := c.readLine(ctx, " > ", q.Completer)
 
 	if err != nil {
 		if err == io.EOF {
 			return answer, err
 		}
+		if isLeftPrompt(err) {
+			return "", err
+		}
 
 		return answer, fmt.Errorf("there was an error reading the stdin: %w", err)
 	}
 
-	if answer == "\n" && q.DefaultAnswer != "" {
+	if answer == "" && q.DefaultAnswer != "" {
 		return q.DefaultAnswer, nil
 	}
 
-	// Remove the ending "\n" before returning
-	return answer[:len(answer)-1], nil
+	return answer, nil
 }
 
 func getScrollWindowHeight(choiceCount int, termHeight int) int {
@@ -281,9 +590,9 @@ func getScrollWindowHeight(choiceCount int, termHeight int) int {
 	return choiceCount - 2
 }
 
-func formatClosedQuestionChoice(label string, highlighted bool) string {
+func (c *Console) formatClosedQuestionChoice(label string, highlighted bool) string {
 	if highlighted {
-		return fmt.Sprintf("\n\033[1000D > %s", highlightedChoiceStyle.Apply(label))
+		return fmt.Sprintf("\n\033[1000D > %s", c.applyStyle(highlightedChoiceStyle, label))
 	}
 
 	return fmt.Sprintf("\n\033[1000D   %s", label)
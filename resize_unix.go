@@ -0,0 +1,14 @@
+//go:build !windows
+
+package styledconsole
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize sends a signal to the channel every time the terminal is resized
+func notifyResize(resized chan<- os.Signal) {
+	signal.Notify(resized, syscall.SIGWINCH)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package styledconsole
+
+import (
+	"os"
+)
+
+// notifyResize does nothing on Windows, where there is no signal when the console is resized.
+// The size of the console is read again on every redraw.
+func notifyResize(resized chan<- os.Signal) {}
@@ -4,11 +4,17 @@ import (
 	"github.com/corentindeboisset/styledconsole/styledprinter"
 )
 
-var greenStyle, yellowStyle, redStyle, highlightedChoiceStyle *styledprinter.OutputStyle
+var greenStyle, yellowStyle, redStyle, highlightedChoiceStyle, suggestionStyle *styledprinter.OutputStyle
 
 func init() {
 	greenStyle = styledprinter.NewOutputStyle("fg=green")
 	redStyle = styledprinter.NewOutputStyle("fg=red")
 	highlightedChoiceStyle = styledprinter.NewOutputStyle("fg=cyan;options=bold,underscore")
 	yellowStyle = styledprinter.NewOutputStyle("fg=yellow")
+	suggestionStyle = styledprinter.NewOutputStyle("fg=gray")
+}
+
+// applyStyle surrounds the text with the escape sequences of the style, if the output of the console supports it
+func (c *Console) applyStyle(style *styledprinter.OutputStyle, text string) string {
+	return style.ApplyWithLevel(text, c.printer.ColorLevel())
 }
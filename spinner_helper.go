@@ -0,0 +1,173 @@
+package styledconsole
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+)
+
+// The frame sets of the spinners, see Spinner.SetFrames()
+var (
+	SpinnerDots   = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerLine   = []string{"-", "\\", "|", "/"}
+	SpinnerCircle = []string{"◐", "◓", "◑", "◒"}
+	SpinnerArrows = []string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}
+	SpinnerBounce = []string{"⠁", "⠂", "⠄", "⠂"}
+)
+
+// spinnerInterval is the delay between two frames of a spinner
+const spinnerInterval = 80 * time.Millisecond
+
+// Spinner displays an animation next to a message while a task of unknown length is running. It is safe for concurrent use.
+// Without a terminal, only the final state of the spinner is printed.
+type Spinner struct {
+	mutex   sync.Mutex
+	console *Console
+	frames  []string
+	frame   int
+	message string
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewSpinner instanciates a spinner with a message, that is displayed once started
+func (c *Console) NewSpinner(message string) *Spinner {
+	return &Spinner{console: c, frames: SpinnerDots, message: message}
+}
+
+// SetFrames changes the animation of the spinner
+func (s *Spinner) SetFrames(frames []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(frames) > 0 {
+		s.frames = frames
+		s.frame = 0
+	}
+}
+
+// SetMessage changes the message displayed next to the animation
+func (s *Spinner) SetMessage(message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.message = message
+	if s.stop != nil {
+		s.draw()
+	}
+}
+
+// Start displays the spinner, and animates it until it is stopped. If the spinner is already running it does nothing.
+func (s *Spinner) Start() {
+	if !s.console.isTerm() {
+		return
+	}
+	s.console.addLiveWidget(s)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	s.console.hideCursor()
+	s.draw()
+
+	go s.animate(s.stop, s.stopped)
+}
+
+// Stop removes the spinner from the terminal
+func (s *Spinner) Stop() {
+	s.end(``)
+}
+
+// Success replaces the spinner with a success mark and a message
+func (s *Spinner) Success(message string) {
+	s.end(fmt.Sprintf(" <fg=green>✔</> %s", message))
+}
+
+// Fail replaces the spinner with a failure mark and a message
+func (s *Spinner) Fail(message string) {
+	s.end(fmt.Sprintf(" <fg=red>✘</> %s", message))
+}
+
+// end stops the animation, and replaces the spinner with a final line if it is not empty.
+// The final line is printed above the other live widgets, like a progress bar displayed at the same time.
+func (s *Spinner) end(finalLine string) {
+	var stopped chan struct{}
+	s.console.replaceLiveWidget(s, func(hidden bool) {
+		if !hidden {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+		}
+
+		if s.stop != nil {
+			// The goroutine draws nothing more once the channel is closed, it finishes when the mutex is released
+			close(s.stop)
+			s.stop, stopped = nil, s.stopped
+			if !hidden {
+				fmt.Fprint(s.console.out, "\033[1000D\033[0K")
+			}
+			s.console.showCursor()
+		}
+		if finalLine != `` {
+			s.console.printer.Write(finalLine, true)
+		}
+	})
+
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+// animate shows the next frame periodically, until the stop channel is closed
+func (s *Spinner) animate(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	for {
+		advance := false
+		select {
+		case <-stop:
+			return
+		case <-resized:
+		case <-ticker.C:
+			advance = true
+		}
+
+		s.mutex.Lock()
+		select {
+		case <-stop:
+		default:
+			if advance {
+				s.frame = (s.frame + 1) % len(s.frames)
+			}
+			s.draw()
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// draw prints the current frame and the message over the previous ones, the mutex must be held.
+// The message is truncated so that the spinner fits on a single row of the terminal.
+func (s *Spinner) draw() {
+	width, _ := s.console.getWinsize()
+	frame := s.frames[s.frame]
+	message := styledprinter.Truncate(s.message, width-3-styledprinter.StringWidth(frame), "…")
+
+	fmt.Fprintf(s.console.out, "\033[1000D %s %s\033[0K", s.console.applyStyle(yellowStyle, frame), message)
+}
@@ -0,0 +1,53 @@
+package styledconsole
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSpinnerWithoutTerminal checks only the final state of a spinner is printed when the output is not a terminal
+func TestSpinnerWithoutTerminal(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithColorLevel(styledprinter.ColorLevelNone))
+
+	spinner := console.NewSpinner("Loading")
+	spinner.Start()
+	spinner.Success("Loaded")
+	spinner = console.NewSpinner("Loading")
+	spinner.Start()
+	spinner.Fail("Failed")
+	spinner.Stop()
+
+	assert.Equal(" ✔ Loaded\n ✘ Failed\n", out.String())
+}
+
+// TestSpinnerInTerminal checks a spinner is animated until it is stopped
+func TestSpinnerInTerminal(t *testing.T) {
+	assert := assert.New(t)
+	out := &bytes.Buffer{}
+	console := New(strings.NewReader(""), out, WithTerminal(true), WithColorLevel(styledprinter.ColorLevelNone))
+
+	spinner := console.NewSpinner("Loading")
+	spinner.SetFrames(SpinnerLine)
+	spinner.Start()
+	time.Sleep(3 * spinnerInterval)
+	spinner.SetMessage("Still loading")
+	spinner.Success("Loaded")
+
+	output := out.String()
+	assert.True(strings.HasPrefix(output, "\033[?25l\033[1000D - Loading\033[0K\033[1000D \\ Loading\033[0K"), output)
+	assert.Contains(output, "Still loading\033[0K")
+	assert.True(strings.HasSuffix(output, "\033[1000D\033[0K\033[?25h\033[?0c ✔ Loaded\n"), output)
+
+	// Once stopped, the spinner is not redrawn anymore
+	out.Reset()
+	time.Sleep(2 * spinnerInterval)
+	spinner.Stop()
+	assert.Equal("", out.String())
+}
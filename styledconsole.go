@@ -1,181 +1,243 @@
 // Package styledconsole helps to make your GUI tools user-friendly with methods to pretty-print text, lists, tables, user prompts, progress bars...
+//
+// The package-level functions read from the standard input and print to the standard output.
+// To use other streams (stderr, a buffer, an SSH session...), create a Console with New().
 package styledconsole
 
 import (
-	"fmt"
-	"strings"
+	"context"
+	"io"
+	"time"
 
 	"github.com/corentindeboisset/styledconsole/styledprinter"
 )
 
+// SetColorLevel overrides the range of colors that can be used on the standard output.
+// Use styledprinter.ColorLevelNone to disable all styles.
+func SetColorLevel(level styledprinter.ColorLevel) {
+	defaultConsole.SetColorLevel(level)
+}
+
+// SetMouse enables or disables the mouse in the Choice and MultiChoice prompts of the standard output, see WithMouse().
+func SetMouse(enabled bool) {
+	defaultConsole.SetMouse(enabled)
+}
+
+// SetAnswerTimeout limits the time the user has to answer the prompts of the standard input, see WithAnswerTimeout().
+func SetAnswerTimeout(timeout time.Duration) {
+	defaultConsole.SetAnswerTimeout(timeout)
+}
+
+// SetInterruptSignal makes the prompts of the standard input send an interrupt signal to the program when Ctrl-C is pressed,
+// see WithInterruptSignal().
+func SetInterruptSignal(enabled bool) {
+	defaultConsole.SetInterruptSignal(enabled)
+}
+
 // Section displays the given string as the title of some command section.
 func Section(title string) {
-	titleLen := len(title)
-	underline := strings.Repeat("=", titleLen)
-
-	styledprinter.Write(fmt.Sprintf("<fg=yellow;options=bold>%s\n%s\n</>", title, underline), true)
+	defaultConsole.Section(title)
 }
 
 // Text displays the given string as regular text. This is useful to render help messages and instructions for the user running the command.
 // This methods support style tags such as "<fg=blue>blue text</>".
 func Text(content string) {
-	styledprinter.Write(content, true)
+	defaultConsole.Text(content)
 }
 
 // Listing displays an list of elements
 func Listing(items []string) {
-	for _, item := range items {
-		styledprinter.Write(fmt.Sprintf(" <fg=yellow>*</> %s", item), true)
-	}
+	defaultConsole.Listing(items)
 }
 
-// Table pretty-prints a table with headers. It does not support multiline cells or sytling.
+// Table pretty-prints a table with headers. The cells can contain line breaks and style tags.
 func Table(headers []string, rows [][]string) {
-	// First we have to determinate the width of every column
-	columnWidths := getColumnWidths(headers, rows)
-	termWidth, _ := getWinsize()
-
-	columnWidths = getAcceptableColumnWidths(columnWidths, termWidth)
-
-	// Prepare the row spearator
-	sectionSeparator := "+"
-	for _, width := range columnWidths {
-		sectionSeparator += fmt.Sprintf("%s+", strings.Repeat("-", width+2))
-	}
-
-	var formattedRows []string
-	formattedRows = append(formattedRows, sectionSeparator)
-	formattedRows = append(formattedRows, formatOneRow(headers, columnWidths))
-	formattedRows = append(formattedRows, sectionSeparator)
-	for _, row := range rows {
-		formattedRows = append(formattedRows, formatOneRow(row, columnWidths))
-	}
-	formattedRows = append(formattedRows, sectionSeparator)
+	defaultConsole.Table(headers, rows)
+}
 
-	fmt.Printf("%s\n", strings.Join(formattedRows, "\n"))
+// TableWithOptions is the same as Table() but the style of the borders, as well as the alignment and the width of each column can be customized.
+func TableWithOptions(headers []string, rows [][]string, options TableOptions) {
+	defaultConsole.TableWithOptions(headers, rows, options)
 }
 
 // NewLine prints a line break.
 func NewLine() {
-	styledprinter.Write("", true)
+	defaultConsole.NewLine()
 }
 
 // NewLines print the given amount of new breaks.
 func NewLines(newLineCount int) {
-	if newLineCount > 0 {
-		styledprinter.Write(strings.Repeat("\n", newLineCount-1), true)
-	}
+	defaultConsole.NewLines(newLineCount)
 }
 
 // Ask prompts a question with the given label.
 // A function can be given to ensure the validity of the response. To allow any response (even empty), put nil as validator.
 func Ask(label string, validator func(string) bool) (string, error) {
-	q := question{
-		Label:         label,
-		IsClosed:      false,
-		IsHidden:      false,
-		DefaultAnswer: "",
-		Validator:     validator,
-	}
-
-	res, err := askQuestion(q)
-	if err != nil {
-		return "", err
-	}
-	return res, nil
-}
-
-// Same as Ask() but if the user's answer is empty, the given defaultAnswer is chosen instead.
+	return defaultConsole.Ask(label, validator)
+}
+
+// AskContext is the same as Ask() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func AskContext(ctx context.Context, label string, validator func(string) bool) (string, error) {
+	return defaultConsole.AskContext(ctx, label, validator)
+}
+
+// AskWithDefault is the same as Ask() but if the user's answer is empty, the given defaultAnswer is chosen instead.
 func AskWithDefault(label string, defaultAnswer string, validator func(string) bool) (string, error) {
-	q := question{
-		Label:         label,
-		IsClosed:      false,
-		IsHidden:      false,
-		DefaultAnswer: defaultAnswer,
-		Validator:     validator,
-	}
-
-	res, err := askQuestion(q)
-	if err != nil {
-		return "", err
-	}
-	return res, nil
-}
-
-// Same as Ask() but the characters typed by the user are not printed in the output, in a linux-style password prompt.
+	return defaultConsole.AskWithDefault(label, defaultAnswer, validator)
+}
+
+// AskWithDefaultContext is the same as AskWithDefault() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func AskWithDefaultContext(ctx context.Context, label string, defaultAnswer string, validator func(string) bool) (string, error) {
+	return defaultConsole.AskWithDefaultContext(ctx, label, defaultAnswer, validator)
+}
+
+// AskWithCompletion is the same as Ask() but the answer can be completed with the Tab key, see Console.AskWithCompletion().
+func AskWithCompletion(label string, completer func(input string) []string) (string, error) {
+	return defaultConsole.AskWithCompletion(label, completer)
+}
+
+// AskWithCompletionContext is the same as AskWithCompletion() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func AskWithCompletionContext(ctx context.Context, label string, completer func(input string) []string) (string, error) {
+	return defaultConsole.AskWithCompletionContext(ctx, label, completer)
+}
+
+// AskHidden is the same as Ask() but the characters typed by the user are not printed in the output, in a linux-style password prompt.
 func AskHidden(label string, validator func(string) bool) (string, error) {
-	q := question{
-		Label:         label,
-		IsClosed:      false,
-		IsHidden:      true,
-		DefaultAnswer: "",
-		Validator:     validator,
-	}
+	return defaultConsole.AskHidden(label, validator)
+}
 
-	res, err := askQuestion(q)
-	if err != nil {
-		return "", err
-	}
-	return res, nil
+// AskHiddenContext is the same as AskHidden() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func AskHiddenContext(ctx context.Context, label string, validator func(string) bool) (string, error) {
+	return defaultConsole.AskHiddenContext(ctx, label, validator)
 }
 
 // Confirm prompts a yes/no question.
 func Confirm(label string) (bool, error) {
-	return askConfirm(label, nil)
+	return defaultConsole.Confirm(label)
+}
+
+// ConfirmContext is the same as Confirm() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func ConfirmContext(ctx context.Context, label string) (bool, error) {
+	return defaultConsole.ConfirmContext(ctx, label)
 }
 
 // ConfirmWithDefault prompts a yes/no question, with a given answer by default if the user's answer is empty.
 func ConfirmWithDefault(label string, defaultAnswer bool) (bool, error) {
-	return askConfirm(label, &defaultAnswer)
+	return defaultConsole.ConfirmWithDefault(label, defaultAnswer)
+}
+
+// ConfirmWithDefaultContext is the same as ConfirmWithDefault() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func ConfirmWithDefaultContext(ctx context.Context, label string, defaultAnswer bool) (bool, error) {
+	return defaultConsole.ConfirmWithDefaultContext(ctx, label, defaultAnswer)
 }
 
 // Choice prints a list of choices the user can choose between.
 // The prompts adapts itself to the size of the terminal.
 func Choice(label string, choices []string) (string, error) {
-	q := question{
-		Label:         label,
-		IsClosed:      true,
-		Choices:       choices,
-		DefaultChoice: -1,
-	}
-
-	choice, err := askQuestion(q)
-	if err != nil {
-		return "", err
-	}
+	return defaultConsole.Choice(label, choices)
+}
 
-	return choice, nil
+// ChoiceContext is the same as Choice() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func ChoiceContext(ctx context.Context, label string, choices []string) (string, error) {
+	return defaultConsole.ChoiceContext(ctx, label, choices)
 }
 
 // ChoiceWithDefault is the same as Choice() but a specific answer index should be given to highlight by default.
 func ChoiceWithDefault(label string, choices []string, defaultAnswer int) (string, error) {
-	q := question{
-		Label:         label,
-		IsClosed:      true,
-		Choices:       choices,
-		DefaultChoice: defaultAnswer,
-	}
+	return defaultConsole.ChoiceWithDefault(label, choices, defaultAnswer)
+}
+
+// ChoiceWithDefaultContext is the same as ChoiceWithDefault() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func ChoiceWithDefaultContext(ctx context.Context, label string, choices []string, defaultAnswer int) (string, error) {
+	return defaultConsole.ChoiceWithDefaultContext(ctx, label, choices, defaultAnswer)
+}
+
+// MultiChoice prints a list of choices the user can select several of, see Console.MultiChoice().
+func MultiChoice(label string, choices []string, defaults []int) ([]string, error) {
+	return defaultConsole.MultiChoice(label, choices, defaults)
+}
 
-	choice, err := askQuestion(q)
-	if err != nil {
-		fmt.Printf("error: %s", err)
-		return "", err
-	}
+// MultiChoiceContext is the same as MultiChoice() but the prompt is stopped once the context is done, and ctx.Err() is returned.
+func MultiChoiceContext(ctx context.Context, label string, choices []string, defaults []int) ([]string, error) {
+	return defaultConsole.MultiChoiceContext(ctx, label, choices, defaults)
+}
 
-	return choice, nil
+// MultiChoiceWithLimits is the same as MultiChoice() but the user must select between min and max choices.
+func MultiChoiceWithLimits(label string, choices []string, defaults []int, min int, max int) ([]string, error) {
+	return defaultConsole.MultiChoiceWithLimits(label, choices, defaults, min, max)
+}
+
+// MultiChoiceWithLimitsContext is the same as MultiChoiceWithLimits() but the prompt is stopped once the context is done,
+// and ctx.Err() is returned.
+func MultiChoiceWithLimitsContext(ctx context.Context, label string, choices []string, defaults []int, min int, max int) ([]string, error) {
+	return defaultConsole.MultiChoiceWithLimitsContext(ctx, label, choices, defaults, min, max)
 }
 
 // Success displays the given string highlighted as a successful message (with a green background and an [OK] label).
 func Success(content string) {
-	styledprinter.WriteBlock(fmt.Sprintf("Success:\n%s", content), "  ", "bg=green;fg=black", true)
+	defaultConsole.Success(content)
 }
 
 // Warning displays the given string highlighted as a warning message (with yellow text and a [Warning] label).
 func Warning(content string) {
-	styledprinter.WriteBlock(fmt.Sprintf("Warning:\n%s", content), "# ", "fg=yellow", true)
+	defaultConsole.Warning(content)
 }
 
 // Error displays the given string highlighted as an error message (with a red background and the [Error] label).
 func Error(content string) {
-	styledprinter.WriteBlock(fmt.Sprintf("Error:\n%s", content), "  ", "bg=red;fg=black", true)
+	defaultConsole.Error(content)
+}
+
+// ProgressStart starts a progress bar of a given duration
+func ProgressStart(totalSteps int) {
+	defaultConsole.ProgressStart(totalSteps)
+}
+
+// ProgressAdvance advances the current progress bar of a given stepCount. If there is no progressBar it does nothing
+func ProgressAdvance(stepCount int) {
+	defaultConsole.ProgressAdvance(stepCount)
+}
+
+// ProgressFinish finishes the current progress bar. If there is no progressBar it does nothing
+func ProgressFinish() {
+	defaultConsole.ProgressFinish()
+}
+
+// NewProgressBar displays a new progress bar with a given amount of steps
+func NewProgressBar(total int) *ProgressBar {
+	return defaultConsole.NewProgressBar(total)
+}
+
+// NewProgressBarWithFormat displays a new progress bar like NewProgressBar, with the given format (see ProgressBar.SetFormat())
+func NewProgressBarWithFormat(total int, format string) *ProgressBar {
+	return defaultConsole.NewProgressBarWithFormat(total, format)
+}
+
+// NewBytesProgressBar displays a new progress bar that follows a transfer of total bytes, see Console.NewBytesProgressBar()
+func NewBytesProgressBar(total int64) *ProgressBar {
+	return defaultConsole.NewBytesProgressBar(total)
+}
+
+// NewMultiProgress instanciates an empty container of progress bars, to display several bars at once
+func NewMultiProgress() *MultiProgress {
+	return defaultConsole.NewMultiProgress()
+}
+
+// NewSpinner instanciates a spinner with a message, that is displayed once started
+func NewSpinner(message string) *Spinner {
+	return defaultConsole.NewSpinner(message)
+}
+
+// Keys returns the decoder of the keys typed in the standard input, see Console.Keys().
+func Keys() *KeyDecoder {
+	return defaultConsole.Keys()
+}
+
+// Writer returns an io.Writer that prints above the progress bars and spinners of the standard output, to be given to
+// log.SetOutput() or to a slog.Handler. The lines are printed once they are complete.
+func Writer() io.Writer {
+	return defaultConsole.Writer()
 }
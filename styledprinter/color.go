@@ -0,0 +1,246 @@
+package styledprinter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorLevel is the range of colors a terminal is able to display
+type ColorLevel int
+
+const (
+	// ColorLevelNone disables all styles, the style tags are simply removed from the text
+	ColorLevelNone ColorLevel = iota
+	// ColorLevel16 is the basic ANSI set of 8 colors and their bright variants
+	ColorLevel16
+	// ColorLevel256 is the xterm palette of 256 colors
+	ColorLevel256
+	// ColorLevelTrueColor is the full 24-bit RGB range
+	ColorLevelTrueColor
+)
+
+var (
+	hexColorRegexp = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+	rgbColorRegexp = regexp.MustCompile(`^rgb\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*\)$`)
+
+	// The RGB values of the 16 basic colors, as rendered by xterm
+	basicPalette = [16][3]uint8{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+		{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	// The intensities used by the 6x6x6 color cube of the 256-color palette
+	cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+)
+
+// DetectColorLevel guesses the range of colors that can be displayed by the given output.
+//
+// The detection follows these rules, by order of priority:
+//   - FORCE_COLOR enables the colors even if the output is not a terminal ("2" for 256 colors, "3" for truecolor), or disables them if it is "0" or "false"
+//   - NO_COLOR disables the colors if it is not empty
+//   - CLICOLOR_FORCE enables the colors if it is not "0", and CLICOLOR disables them if it is "0"
+//   - the colors are disabled if the output is not a terminal, or if TERM is "dumb"
+//   - COLORTERM and TERM are used to detect support for 256 colors or truecolor
+func DetectColorLevel(out io.Writer) ColorLevel {
+	if forceColor, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(forceColor) {
+		case "0", "false":
+			return ColorLevelNone
+		case "2":
+			return ColorLevel256
+		case "3":
+			return ColorLevelTrueColor
+		default:
+			return detectTermColorLevel()
+		}
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return ColorLevelNone
+	}
+
+	if cliColorForce := os.Getenv("CLICOLOR_FORCE"); cliColorForce != "" && cliColorForce != "0" {
+		return detectTermColorLevel()
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return ColorLevelNone
+	}
+
+	file, ok := out.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(int(file.Fd())) || os.Getenv("TERM") == "dumb" {
+		return ColorLevelNone
+	}
+
+	return detectTermColorLevel()
+}
+
+// detectTermColorLevel guesses the range of colors supported by the terminal from the COLORTERM and TERM variables
+func detectTermColorLevel() ColorLevel {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorLevelTrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return ColorLevel256
+	}
+
+	return ColorLevel16
+}
+
+// getColorCodes returns the [set, unset] escape codes of a color, adapted to the given color level.
+// The color can either be a name, a 256-palette index ("208"), an hexadecimal value ("#ff8800") or a rgb value ("rgb(255,136,0)").
+func getColorCodes(value string, background bool, level ColorLevel) ([2]string, bool) {
+	namedColors := availableForegroundColors
+	if background {
+		namedColors = availableBackgroundColors
+	}
+	if codes, ok := namedColors[value]; ok {
+		return codes, true
+	}
+
+	unset := "39"
+	if background {
+		unset = "49"
+	}
+
+	if index, err := strconv.Atoi(value); err == nil {
+		if index < 0 || index > 255 {
+			return [2]string{}, false
+		}
+		if level >= ColorLevel256 {
+			return [2]string{fmt.Sprintf("%s;5;%d", extendedColorPrefix(background), index), unset}, true
+		}
+		if index < 16 {
+			return [2]string{basicColorCode(index, background), unset}, true
+		}
+
+		return [2]string{basicColorCode(nearestBasicColor(paletteToRGB(index)), background), unset}, true
+	}
+
+	rgb, ok := parseRGB(value)
+	if !ok {
+		return [2]string{}, false
+	}
+
+	switch {
+	case level >= ColorLevelTrueColor:
+		return [2]string{fmt.Sprintf("%s;2;%d;%d;%d", extendedColorPrefix(background), rgb[0], rgb[1], rgb[2]), unset}, true
+	case level == ColorLevel256:
+		return [2]string{fmt.Sprintf("%s;5;%d", extendedColorPrefix(background), nearestPaletteColor(rgb)), unset}, true
+	default:
+		return [2]string{basicColorCode(nearestBasicColor(rgb), background), unset}, true
+	}
+}
+
+// parseRGB extracts the red, green and blue components of a color in the "#rgb", "#rrggbb" or "rgb(r,g,b)" formats
+func parseRGB(value string) ([3]uint8, bool) {
+	if matches := hexColorRegexp.FindStringSubmatch(value); matches != nil {
+		hex := matches[1]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+
+		var rgb [3]uint8
+		for i := range rgb {
+			component, _ := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+			rgb[i] = uint8(component)
+		}
+		return rgb, true
+	}
+
+	if matches := rgbColorRegexp.FindStringSubmatch(value); matches != nil {
+		var rgb [3]uint8
+		for i := range rgb {
+			component, err := strconv.ParseUint(matches[i+1], 10, 8)
+			if err != nil {
+				return rgb, false
+			}
+			rgb[i] = uint8(component)
+		}
+		return rgb, true
+	}
+
+	return [3]uint8{}, false
+}
+
+func extendedColorPrefix(background bool) string {
+	if background {
+		return "48"
+	}
+
+	return "38"
+}
+
+// basicColorCode returns the escape code of one of the 16 basic colors
+func basicColorCode(index int, background bool) string {
+	code := 30 + index
+	if index >= 8 {
+		code = 90 + index - 8
+	}
+	if background {
+		code += 10
+	}
+
+	return strconv.Itoa(code)
+}
+
+// paletteToRGB returns the RGB value of a color of the 256-color palette
+func paletteToRGB(index int) [3]uint8 {
+	switch {
+	case index < 16:
+		return basicPalette[index]
+	case index < 232:
+		index -= 16
+		return [3]uint8{cubeLevels[index/36], cubeLevels[(index/6)%6], cubeLevels[index%6]}
+	default:
+		gray := uint8(8 + 10*(index-232))
+		return [3]uint8{gray, gray, gray}
+	}
+}
+
+// nearestPaletteColor returns the index of the closest color in the 256-color palette, ignoring the 16 basic colors
+// since their actual value depends on the theme of the terminal
+func nearestPaletteColor(rgb [3]uint8) int {
+	nearest := 16
+	nearestDistance := -1
+	for index := 16; index < 256; index++ {
+		if distance := colorDistance(rgb, paletteToRGB(index)); nearestDistance < 0 || distance < nearestDistance {
+			nearest = index
+			nearestDistance = distance
+		}
+	}
+
+	return nearest
+}
+
+// nearestBasicColor returns the index of the closest color among the 16 basic colors
+func nearestBasicColor(rgb [3]uint8) int {
+	nearest := 0
+	nearestDistance := -1
+	for index, candidate := range basicPalette {
+		if distance := colorDistance(rgb, candidate); nearestDistance < 0 || distance < nearestDistance {
+			nearest = index
+			nearestDistance = distance
+		}
+	}
+
+	return nearest
+}
+
+// colorDistance returns the squared euclidean distance between two colors
+func colorDistance(a [3]uint8, b [3]uint8) int {
+	distance := 0
+	for i := range a {
+		delta := int(a[i]) - int(b[i])
+		distance += delta * delta
+	}
+
+	return distance
+}
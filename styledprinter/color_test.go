@@ -0,0 +1,119 @@
+package styledprinter
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseRGB checks we can read hexadecimal and rgb() colors
+func TestParseRGB(t *testing.T) {
+	assert := assert.New(t)
+
+	rgb, ok := parseRGB("#ff8800")
+	assert.True(ok)
+	assert.Equal([3]uint8{255, 136, 0}, rgb)
+
+	rgb, ok = parseRGB("#f80")
+	assert.True(ok)
+	assert.Equal([3]uint8{255, 136, 0}, rgb)
+
+	rgb, ok = parseRGB("rgb(10, 20,30)")
+	assert.True(ok)
+	assert.Equal([3]uint8{10, 20, 30}, rgb)
+
+	_, ok = parseRGB("rgb(10,20,300)")
+	assert.False(ok)
+	_, ok = parseRGB("#ff880")
+	assert.False(ok)
+	_, ok = parseRGB("orange")
+	assert.False(ok)
+}
+
+// TestColorCodes checks the escape codes of colors for every color level
+func TestColorCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	codes, ok := getColorCodes("red", false, ColorLevelTrueColor)
+	assert.True(ok)
+	assert.Equal([2]string{"31", "39"}, codes)
+
+	codes, ok = getColorCodes("#ff8800", false, ColorLevelTrueColor)
+	assert.True(ok)
+	assert.Equal([2]string{"38;2;255;136;0", "39"}, codes)
+
+	codes, ok = getColorCodes("rgb(255,136,0)", true, ColorLevel256)
+	assert.True(ok)
+	assert.Equal([2]string{"48;5;208", "49"}, codes)
+
+	codes, ok = getColorCodes("#ff8800", false, ColorLevel16)
+	assert.True(ok)
+	assert.Equal([2]string{"33", "39"}, codes)
+
+	codes, ok = getColorCodes("208", false, ColorLevel256)
+	assert.True(ok)
+	assert.Equal([2]string{"38;5;208", "39"}, codes)
+
+	codes, ok = getColorCodes("9", true, ColorLevel16)
+	assert.True(ok)
+	assert.Equal([2]string{"101", "49"}, codes)
+
+	codes, ok = getColorCodes("21", false, ColorLevel16)
+	assert.True(ok)
+	assert.Equal([2]string{"34", "39"}, codes)
+
+	_, ok = getColorCodes("256", false, ColorLevelTrueColor)
+	assert.False(ok)
+	_, ok = getColorCodes("blau", false, ColorLevelTrueColor)
+	assert.False(ok)
+}
+
+// TestApplyExtendedColors checks we can apply a style with extended colors
+func TestApplyExtendedColors(t *testing.T) {
+	mystyle := NewOutputStyle("fg=#FF8800;bg=rgb(10,20,30)")
+	assert.Equal(
+		t,
+		"\033[38;2;255;136;0;48;2;10;20;30mThis is a text.\033[39;49m",
+		mystyle.ApplyWithLevel("This is a text.", ColorLevelTrueColor),
+	)
+}
+
+// TestDetectColorLevel checks the color level is detected from the environment
+func TestDetectColorLevel(t *testing.T) {
+	assert := assert.New(t)
+	for _, name := range []string{"FORCE_COLOR", "NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "COLORTERM"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	t.Setenv("TERM", "xterm")
+	out := &bytes.Buffer{}
+
+	// Not a terminal
+	assert.Equal(ColorLevelNone, DetectColorLevel(out))
+
+	t.Setenv("CLICOLOR_FORCE", "1")
+	assert.Equal(ColorLevel16, DetectColorLevel(out))
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(ColorLevel256, DetectColorLevel(out))
+	t.Setenv("COLORTERM", "truecolor")
+	assert.Equal(ColorLevelTrueColor, DetectColorLevel(out))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.Equal(ColorLevelNone, DetectColorLevel(out))
+
+	t.Setenv("FORCE_COLOR", "2")
+	assert.Equal(ColorLevel256, DetectColorLevel(out))
+	t.Setenv("FORCE_COLOR", "0")
+	assert.Equal(ColorLevelNone, DetectColorLevel(out))
+}
+
+// TestFormatWithoutColors checks the tags are removed when colors are disabled
+func TestFormatWithoutColors(t *testing.T) {
+	assert.Equal(
+		t,
+		[]string{"awesome text"},
+		formatTextWithLevel("<fg=red;href=http://github.com>awesome</> <bg=#ff8800>text</>", 20, 0, "", ColorLevelNone),
+	)
+}
@@ -1,64 +1,117 @@
 package styledprinter
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 )
 
 var (
-	// escapeRegexp  = regexp.MustCompile(`[^\\]?<`)
-	tagRegexp     = regexp.MustCompile(`(?i)<([a-z][^<>]*|/([a-z][^<>]*)?)>`)
-	lineEndRegexp = regexp.MustCompile(` *(\r?\n)`)
+	tagRegexp      = regexp.MustCompile(`(?i)<([a-z][^<>]*|/([a-z][^<>]*)?)>`)
+	lineEndRegexp  = regexp.MustCompile(` *(\r?\n)`)
+	escapeReplacer = strings.NewReplacer(`\`, `\\`, `<`, `\<`)
 )
 
-// escape will prepend all '<' with a backslash
-// func escape(text string) string {
-// 	return escapeRegexp.ReplaceAllString(text, `$1\<`)
-// }
-
-// escapeTrailingBackslash removes any trailing backslashes while keeping the length of the string
-// FIXME: this function is not used, should it be removed?
-func escapeTrailingBackslash(text string) string {
-	textLen := len(text)
-	if textLen > 0 && text[textLen-1:] == `\` {
-		newText := strings.TrimRight(text, `\`)
-		newText = strings.TrimRight(newText, "\x00")
-		newText = fmt.Sprintf("%s%s", newText, strings.Repeat("\x00", textLen-len(newText)))
-
-		return newText
+// Escape prepends a backslash to every '<' and '\' of a text, so that it is printed as-is by the formatter.
+//
+// The formatter follows these rules:
+//   - "\<" is printed as a '<' that can never start a style tag
+//   - "\\" is printed as a single '\'
+//   - a backslash followed by any other character is printed as-is
+//
+// Any text coming from the user should thus be escaped before being surrounded with style tags.
+func Escape(text string) string {
+	return escapeReplacer.Replace(text)
+}
+
+// formatTextWithLevel finds all tags and replaces them with the escape sequences of the given color level,
+// and adds newlines when necessary to ensure the output is fine in a given terminal.
+// Every line but the first is indented with the given amount of columns.
+func formatTextWithLevel(text string, width int, indent int, baseStyleString string, level ColorLevel) []string {
+	if width < 1 {
+		width = 1
 	}
 
-	return text
+	segments := parseWithBaseStyle(text, baseStyleString)
+	return renderLines(wrapSegments(segments, width, indent), segments, width, indent, level)
 }
 
-// formatText find all tags and replace them with the correct escape sequences,
-// and adds newlines when necessary to ensure the output is fine in a given terminal
-func formatText(text string, width int, baseStyleString string) []string {
-	var offset int
+// Segment is a piece of text that is displayed with a single style
+type Segment struct {
+	Text  string
+	Style OutputStyle
+}
 
-	output := []string{""}
-	currentLineLength := 0
+// Parse splits a text containing style tags into segments of unescaped text, each with the style it should be displayed with.
+// This is useful to build custom layouts, with Segment.Style.ApplyWithLevel() to render each segment.
+func Parse(text string) []Segment {
+	return parseWithBaseStyle(text, "")
+}
 
-	tagMatches := tagRegexp.FindAllSubmatchIndex([]byte(text), -1)
+// parseWithBaseStyle is the same as Parse, but the styles of the segments are merged with the base style
+func parseWithBaseStyle(text string, baseStyleString string) []Segment {
+	var segments []Segment
 	styleStack := newOutputStyleStack(baseStyleString)
 
-	for _, tagIndexes := range tagMatches {
-		if tagIndexes[0] == 0 && text[len(text)-1] == '\\' {
+	walkTags(text, &styleStack, func(textPart string) {
+		if textPart != `` {
+			segments = append(segments, Segment{Text: textPart, Style: styleStack.GetCurrent()})
+		}
+	})
+
+	return segments
+}
+
+// stripTags removes all the valid style tags from a text
+func stripTags(text string) string {
+	var output strings.Builder
+	styleStack := newOutputStyleStack("")
+
+	walkTags(text, &styleStack, func(textPart string) {
+		output.WriteString(textPart)
+	})
+
+	return output.String()
+}
+
+// walkTags goes through a text and updates the stack of styles for every tag that is found.
+// The text between the tags, unescaped, as well as the invalid tags, are given to writeText.
+func walkTags(text string, styleStack *outputStyleStack, writeText func(string)) {
+	var pendingText strings.Builder
+
+	// A tag cannot contain a '<', so the tags that are escaped never hide a valid one
+	tagMatches := tagRegexp.FindAllStringSubmatchIndex(text, -1)
+
+	for i := 0; i < len(text); {
+		if text[i] == '\\' && i+1 < len(text) && (text[i+1] == '\\' || text[i+1] == '<') {
+			// Escaped character, it is written without its backslash
+			pendingText.WriteByte(text[i+1])
+			i += 2
+			continue
+		}
+
+		for len(tagMatches) > 0 && tagMatches[0][0] < i {
+			tagMatches = tagMatches[1:]
+		}
+		if len(tagMatches) == 0 || tagMatches[0][0] != i {
+			pendingText.WriteByte(text[i])
+			i++
 			continue
 		}
 
 		// Write text up to the tag
-		addStringWithStyle(text[offset:tagIndexes[0]], width, &output, &currentLineLength, styleStack)
-		offset = tagIndexes[1]
+		writeText(pendingText.String())
+		pendingText.Reset()
+
+		tagIndexes := tagMatches[0]
+		tag := text[tagIndexes[0]:tagIndexes[1]]
+		i = tagIndexes[1]
 
 		// Opening tag ?
 		tagName := ``
-		// openingTag := tagIndexes
-		openingTag := text[tagIndexes[2]:tagIndexes[3]][0] != '/'
+		openingTag := tag[1] != '/'
 		if openingTag {
 			tagName = text[tagIndexes[2]:tagIndexes[3]]
-		} else if tagIndexes[4] > 0 && tagIndexes[5] > 0 {
+		} else if tagIndexes[4] >= 0 {
 			tagName = text[tagIndexes[4]:tagIndexes[5]]
 		}
 
@@ -75,106 +128,11 @@ func formatText(text string, width int, baseStyleString string) []string {
 
 			if !validTag {
 				// If the tag is invalid, we write its text
-				addStringWithStyle(text[tagIndexes[0]:tagIndexes[1]], width, &output, &currentLineLength, styleStack)
+				writeText(tag)
 			}
 		}
 	}
 
 	// Write the end of the text
-	addStringWithStyle(text[offset:], width, &output, &currentLineLength, styleStack)
-
-	for i, line := range output {
-		output[i] = strings.ReplaceAll(line, "\x00", `\`)
-		output[i] = strings.ReplaceAll(line, `\<`, `<`)
-	}
-
-	return output
-}
-
-func getSubstring(s string, start int, end int) string {
-	if start > len(s) {
-		return ``
-	}
-	if end > len(s) {
-		return s[start:]
-	}
-
-	return s[start:end]
-}
-
-// This function is pretty bad, it should be much more clean and thoroughly tested
-func addStringWithStyle(text string, width int, output *[]string, lastLineLength *int, stack outputStyleStack) {
-	// First, handle invalid argument cases
-	if text == `` || width == 0 || output == nil {
-		return
-	}
-
-	// First cleanup text and replace line endings with \n, then split the lines
-	sourceLines := strings.Split(lineEndRegexp.ReplaceAllString(text, "\n"), "\n")
-
-	var splitLines []string
-
-	if *lastLineLength < 0 {
-		// The lastLineLength was invalid, we try to infer it from *output
-		// It's not optimal because the elements of output contain escape codes
-		*lastLineLength = 0
-		if len(*output) > 0 {
-			*lastLineLength = len((*output)[len(*output)-1])
-		}
-	} else if *lastLineLength > width {
-		splitLines = append(splitLines, "")
-		*lastLineLength = width
-	} else if *lastLineLength > 0 && *lastLineLength+len(sourceLines[0]) > width {
-		// If required, split the first line in two
-		splitLines = append(
-			splitLines,
-			getSubstring(sourceLines[0], 0, width-*lastLineLength),
-		)
-		sourceLines[0] = getSubstring(sourceLines[0], width-*lastLineLength, len(sourceLines[0]))
-	}
-
-	// Then split all the other lines.
-	for _, line := range sourceLines {
-		nbSublines := len(line) / width
-		for j := 0; j <= nbSublines; j++ {
-			splitLines = append(splitLines, getSubstring(line, j*width, (j+1)*width))
-		}
-	}
-
-	// Remove all empty elements (=newLines) but one at the end of splitLines
-	textHasNewLine := false
-	for i := len(splitLines) - 1; i >= 0; i-- {
-		if len(splitLines[i]) > 0 {
-			splitLines = splitLines[0 : i+1]
-			break
-		}
-		textHasNewLine = true
-	}
-	if textHasNewLine {
-		splitLines = append(splitLines, "")
-	}
-
-	// Fill the lines with spaces
-	for i, line := range splitLines[:len(splitLines)-1] {
-		if i == 0 && (len(line)+*lastLineLength) < width {
-			// Special case for the first line that has to takes into account currentLineLength
-			splitLines[i] = line + strings.Repeat(" ", width-len(line)-*lastLineLength)
-		} else if i > 0 && len(line) < width {
-			splitLines[i] = line + strings.Repeat(" ", width-len(line))
-		}
-	}
-
-	for i, line := range splitLines {
-		if i == 0 && len(*output) > 0 {
-			(*output)[len(*output)-1] += stack.GetCurrent().Apply(line)
-			*lastLineLength += len(line)
-		} else if len(line) > 0 {
-			// Then we decorate each line
-			*output = append(*output, stack.GetCurrent().Apply(line))
-			*lastLineLength = len(line)
-		} else {
-			*output = append(*output, "")
-			*lastLineLength = 0
-		}
-	}
+	writeText(pendingText.String())
 }
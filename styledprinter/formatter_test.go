@@ -1,99 +1,56 @@
 package styledprinter
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-// TestGetSubstring checks we can extract a substring safely
-func TestGetSubstring(t *testing.T) {
-	assert := assert.New(t)
-	assert.Equal("aaa", getSubstring("zaaaz", 1, 4))
-	assert.Equal("aaaz", getSubstring("zaaaz", 1, 50))
-	assert.Equal("", getSubstring("zaaaz", 50, 51))
-}
-
-// TestEscapeTrailingBackslash checks we can remove trailing "\"" from texts
-func TestEscapeTrailingBackslash(t *testing.T) {
-	assert := assert.New(t)
-
-	assert.Equal("noop", escapeTrailingBackslash("noop"))
-	assert.Equal("super super\x00\x00", escapeTrailingBackslash("super super\\\\"))
-	assert.Equal("super \x00 soupaire \x00 awesome \x00", escapeTrailingBackslash("super \x00 soupaire \x00 awesome \x00"))
-	assert.Equal("super \x00 soupaire \x00 awesome \x00\x00\x00", escapeTrailingBackslash("super \x00 soupaire \x00 awesome \x00\\\\"))
-}
-
-// TestFormatWithStyleWithoutTextBefore checks there are no errors of newline
-func TestFormatWithStyleWithoutTextBefore(t *testing.T) {
+// TestFormatWordWrapping checks the lines are broken between words whenever possible
+func TestFormatWordWrapping(t *testing.T) {
 	assert := assert.New(t)
 	width := 20
-	stack := newOutputStyleStack("")
 
-	// Test line-cutting
-	output := []string{}
-	lastLineLength := 0
-	addStringWithStyle("abc", width, &output, &lastLineLength, stack)
-	assert.Equal(3, lastLineLength)
-	assert.Equal([]string{"abc"}, output)
-
-	output = []string{}
-	lastLineLength = 0
-	addStringWithStyle("supertoto\nabc\n", width, &output, &lastLineLength, stack)
-	assert.Equal(0, lastLineLength)
-	assert.Equal([]string{"supertoto           ", "abc                 ", ""}, output)
-
-	output = []string{}
-	lastLineLength = 0
-	addStringWithStyle("abc\n\n\n", width, &output, &lastLineLength, stack)
-	assert.Equal(0, lastLineLength)
-	assert.Equal([]string{"abc                 ", ""}, output)
-
-	output = []string{"iiiii"}
-	lastLineLength = 5
-	addStringWithStyle("super super super super super super super super super super", width, &output, &lastLineLength, stack)
-	assert.Equal(4, lastLineLength)
 	assert.Equal(
-		[]string{"iiiiisuper super sup", "er super super super", " super super super s", "uper"},
-		output,
+		[]string{"super super super   ", "super super super   ", "super"},
+		formatTextWithLevel("super super super super super super super", width, 0, "", ColorLevel16),
+	)
+	assert.Equal(
+		[]string{"a well-known multi- ", "line-aware text"},
+		formatTextWithLevel("a well-known multi-line-aware text", width, 0, "", ColorLevel16),
+	)
+	assert.Equal(
+		[]string{"a                   ", "verylongwordthatdoes", "notfitonaline"},
+		formatTextWithLevel("a verylongwordthatdoesnotfitonaline", width, 0, "", ColorLevel16),
 	)
+	assert.Equal(
+		[]string{"supertoto           ", "abc                 ", ""},
+		formatTextWithLevel("supertoto\nabc\n", width, 0, "", ColorLevel16),
+	)
+	assert.Equal([]string{"  indented  "}, formatTextWithLevel("  indented  ", width, 0, "", ColorLevel16))
+	assert.Equal([]string{"a -- b"}, formatTextWithLevel("a -- b", width, 0, "", ColorLevel16))
 
 	// Test with style
-	stack.Push("bg=green;fg=red")
-	output = []string{"iiiii"}
-	lastLineLength = 5
-	addStringWithStyle("super super super super super super", width, &output, &lastLineLength, stack)
-	assert.Equal(0, lastLineLength)
 	assert.Equal(
-		[]string{"iiiii\x1b[31;42msuper super sup\x1b[39;49m", "\x1b[31;42mer super super super\x1b[39;49m", ""},
-		output,
+		[]string{"\x1b[31;42msuper super super   \x1b[39;49m", "\x1b[31;42msuper\x1b[39;49m"},
+		formatTextWithLevel("<bg=green;fg=red>super super super super</>", width, 0, "", ColorLevel16),
+	)
+	assert.Equal(
+		[]string{"super \x1b[31msuper\x1b[39m super\x1b[31m   \x1b[39m", "\x1b[31msuper\x1b[39m"},
+		formatTextWithLevel("super <fg=red>super</> super<fg=red> super</>", width, 0, "", ColorLevel16),
 	)
-	stack.PopCurrent()
 
-	// Test edge-cases
-	output = []string{""}
-	lastLineLength = 0
-	addStringWithStyle(" ", width, &output, &lastLineLength, stack)
-	assert.Equal(1, lastLineLength)
-	assert.Equal([]string{" "}, output)
-
-	output = []string{"abcdeabcde"}
-	lastLineLength = 10
-	addStringWithStyle("", width, &output, &lastLineLength, stack)
-	assert.Equal(10, lastLineLength)
-	assert.Equal([]string{"abcdeabcde"}, output)
-
-	output = []string{"super super super super super super super super super long line"}
-	lastLineLength = 63
-	addStringWithStyle("abc", width, &output, &lastLineLength, stack)
-	assert.Equal(3, lastLineLength)
-	assert.Equal([]string{"super super super super super super super super super long line", "abc"}, output)
-
-	output = []string{"iii"}
-	lastLineLength = -10
-	addStringWithStyle("abc", width, &output, &lastLineLength, stack)
-	assert.Equal(6, lastLineLength)
-	assert.Equal([]string{"iiiabc"}, output)
+	// Test with a hanging indent
+	assert.Equal(
+		[]string{" * first item with a", "   long text that is", "   wrapped"},
+		formatTextWithLevel(" * first item with a long text that is wrapped", width, 3, "", ColorLevelNone),
+	)
+	assert.Equal(
+		[]string{"abcdefghij", "   klmnopq", "   rstuvwx", "   yz"},
+		formatTextWithLevel("abcdefghijklmnopqrstuvwxyz", 10, 3, "", ColorLevelNone),
+	)
 }
 
 // TestFormatTextWithoutDefault checks we can render a full text using style tags
@@ -103,43 +60,43 @@ func TestFormatTextWithoutDefault(t *testing.T) {
 
 	assert.Equal(
 		[]string{"great text"},
-		formatText("great text", width, ""),
+		formatTextWithLevel("great text", width, 0, "", ColorLevel16),
 	)
 	assert.Equal(
 		[]string{"awesome text        ", "on                  ", "multiple lines."},
-		formatText("awesome text\non\nmultiple lines.", width, ""),
+		formatTextWithLevel("awesome text\non\nmultiple lines.", width, 0, "", ColorLevel16),
 	)
 	assert.Equal(
 		[]string{"\x1b[31mawesome text\x1b[39m"},
-		formatText("<fg=red>awesome text</>", width, ""),
+		formatTextWithLevel("<fg=red>awesome text</>", width, 0, "", ColorLevel16),
 	)
 	assert.Equal(
 		[]string{
 			"Some                          ",
 			"text                          ",
 			"that can handle \x1b[31mmulti-line    \x1b[39m",
-			"\x1b[31mstyling.\x1b[39m This is a very long l",
-			"ine.",
+			"\x1b[31mstyling.\x1b[39m This is a very long  ",
+			"line.",
 		},
-		formatText("Some\ntext\nthat can handle <fg=red>multi-line\nstyling.</> This is a very long line.", 30, ""),
+		formatTextWithLevel("Some\ntext\nthat can handle <fg=red>multi-line\nstyling.</> This is a very long line.", 30, 0, "", ColorLevel16),
 	)
 	assert.Equal(
 		[]string{
-			"awesome text \x1b[31mwith \x1b[39m\x1b[44mim\x1b[49m",
-			"\x1b[44mbricated styles\x1b[49m\x1b[31m and \x1b[39m",
-			"\x1b[31mon                  \x1b[39m",
+			"awesome text \x1b[31mwith   \x1b[39m",
+			"\x1b[44mimbricated styles\x1b[49m\x1b[31m   \x1b[39m",
+			"\x1b[31mand on              \x1b[39m",
 			"\x1b[31mmultiple\x1b[39m lines.",
 		},
-		formatText("awesome text <fg=red>with <bg=blue>imbricated styles</> and on\nmultiple</> lines.", width, ""),
+		formatTextWithLevel("awesome text <fg=red>with <bg=blue>imbricated styles</> and on\nmultiple</> lines.", width, 0, "", ColorLevel16),
 	)
 
 	// Test edge-cases
-	assert.Equal([]string{""}, formatText("", width, ""))
-	assert.Equal([]string{""}, formatText("<fg=red></>", width, ""))
-	assert.Equal([]string{"qsdf"}, formatText("<fg=wrong>qsdf</>", width, ""))
-	assert.Equal([]string{"<toto=titi>qsdf</fg=", "blue>"}, formatText("<toto=titi>qsdf</fg=blue>", width, ""))
-	assert.Equal([]string{"\x1b[34mtesttest\x1b[39m"}, formatText("<fg=blue>testtest", width, ""))
-	assert.Equal([]string{"testt</fg=blue>est"}, formatText("testt</fg=blue>est", width, ""))
+	assert.Equal([]string{""}, formatTextWithLevel("", width, 0, "", ColorLevel16))
+	assert.Equal([]string{""}, formatTextWithLevel("<fg=red></>", width, 0, "", ColorLevel16))
+	assert.Equal([]string{"qsdf"}, formatTextWithLevel("<fg=wrong>qsdf</>", width, 0, "", ColorLevel16))
+	assert.Equal([]string{"<toto=titi>qsdf</fg=", "blue>"}, formatTextWithLevel("<toto=titi>qsdf</fg=blue>", width, 0, "", ColorLevel16))
+	assert.Equal([]string{"\x1b[34mtesttest\x1b[39m"}, formatTextWithLevel("<fg=blue>testtest", width, 0, "", ColorLevel16))
+	assert.Equal([]string{"testt</fg=blue>est"}, formatTextWithLevel("testt</fg=blue>est", width, 0, "", ColorLevel16))
 }
 
 // TestFormatTextWithDefault checks that we can format text with a default style
@@ -149,17 +106,116 @@ func TestFormatTextWithDefault(t *testing.T) {
 
 	assert.Equal(
 		[]string{"\x1b[31;42mawesome text\x1b[39;49m"},
-		formatText("<fg=red>awesome text</>", width, "bg=green;fg=blue"),
+		formatTextWithLevel("<fg=red>awesome text</>", width, 0, "bg=green;fg=blue", ColorLevel16),
 	)
 	assert.Equal(
 		[]string{"\x1b[34;42mawesome \x1b[39;49m\x1b[31;42mtext\x1b[39;49m"},
-		formatText("awesome <fg=red>text</>", width, "bg=green;fg=blue"),
+		formatTextWithLevel("awesome <fg=red>text</>", width, 0, "bg=green;fg=blue", ColorLevel16),
 	)
 	assert.Equal(
 		[]string{
 			"\x1b[34;42mawesome \x1b[39;49m\x1b[31;42mtext\x1b[39;49m\x1b[34;42m        \x1b[39;49m",
 			"\x1b[34;42mwith \x1b[39;49m\x1b[33;42mmultiple lines\x1b[39;49m",
 		},
-		formatText("awesome <fg=red>text</>\nwith <fg=yellow>multiple lines</>", width, "bg=green;fg=blue"),
+		formatTextWithLevel("awesome <fg=red>text</>\nwith <fg=yellow>multiple lines</>", width, 0, "bg=green;fg=blue", ColorLevel16),
+	)
+}
+
+// TestStrip checks the style tags can be removed from a text
+func TestStrip(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("great text", Strip("great text"))
+	assert.Equal("awesome text with imbricated styles", Strip("awesome <fg=red>text <bg=blue>with</> imbricated</fg=red> styles"))
+	assert.Equal("<toto=titi>qsdf</fg=blue>", Strip("<toto=titi>qsdf</fg=blue>"))
+	assert.Equal("multi\nline", Strip("<fg=red>multi\nline</>"))
+}
+
+// TestFormat checks a text can be formatted without being printed
+func TestFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"\x1b[31mawesome   \x1b[39m", "\x1b[31mtext\x1b[39m"}, Format("<fg=red>awesome\ntext</>", 10))
+
+	printer := NewPrinter(&bytes.Buffer{})
+	printer.SetColorLevel(ColorLevelNone)
+	assert.Equal([]string{"awesome   ", "text"}, printer.Format("<fg=red>awesome\ntext</>", 10))
+}
+
+// TestEscape checks the escaped characters are printed as-is
+func TestEscape(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`\<div>`, Escape("<div>"))
+	assert.Equal(`C:\\path\\`, Escape(`C:\path\`))
+	assert.Equal([]string{"<div>"}, formatTextWithLevel(`\<div>`, 20, 0, "", ColorLevel16))
+	assert.Equal([]string{"\x1b[31m<fg=blue>\x1b[39m"}, formatTextWithLevel(`<fg=red>\<fg=blue></>`, 20, 0, "", ColorLevel16))
+	assert.Equal([]string{`C:\path\`}, formatTextWithLevel(`C:\path\\`, 20, 0, "", ColorLevel16))
+	assert.Equal([]string{`\<`}, formatTextWithLevel(`\\\<`, 20, 0, "", ColorLevel16))
+	assert.Equal([]string{`\\x`}, formatTextWithLevel(`\\\x`, 20, 0, "", ColorLevel16))
+	assert.Equal("<fg=red>text</>", Strip(Escape("<fg=red>text</>")))
+}
+
+// FuzzEscape checks that any escaped text is printed unchanged
+func FuzzEscape(f *testing.F) {
+	for _, seed := range []string{"", "text", "<fg=red>text</>", "<div>", `C:\path\`, `\<`, `<\`, "</>", "a\nb"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		if Strip(Escape(text)) != text {
+			t.Errorf("Strip(Escape(%q)) = %q", text, Strip(Escape(text)))
+		}
+
+		if strings.ContainsAny(text, "\r\n") {
+			// Line endings are normalized by the formatter
+			return
+		}
+		formatted := Format(Escape(text), len(text)+1)
+		if len(formatted) != 1 || formatted[0] != text {
+			t.Errorf("Format(Escape(%q)) = %q", text, formatted)
+		}
+	})
+}
+
+// TestParse checks a text can be split in segments of a single style
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]Segment(nil), Parse(""))
+	assert.Equal(
+		[]Segment{
+			{Text: "awesome "},
+			{Text: "<text>", Style: OutputStyle{foreground: "red", handleHrefGracefully: true}},
+			{Text: " again"},
+		},
+		Parse(`awesome <fg=red>\<text></> again`),
+	)
+}
+
+// TestFormatWideCharacters checks the lines are split on the displayed width of the text
+func TestFormatWideCharacters(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"日本語で", "す"}, formatTextWithLevel("日本語です", 8, 0, "", ColorLevel16))
+	assert.Equal([]string{"日本語 ", "です"}, formatTextWithLevel("日本語です", 7, 0, "", ColorLevel16))
+	assert.Equal([]string{"👍👍  ", "troi\u0308s"}, formatTextWithLevel("👍👍\ntroi\u0308s", 6, 0, "", ColorLevel16))
+	assert.Equal([]string{"ab\x1b[31m日 \x1b[39m", "\x1b[31m本\x1b[39m"}, formatTextWithLevel("ab<fg=red>日本</>", 5, 0, "", ColorLevel16))
+}
+
+// TestWrap checks the segments are split in lines between the words, keeping their style
+func TestWrap(t *testing.T) {
+	assert := assert.New(t)
+
+	red := OutputStyle{foreground: "red", handleHrefGracefully: true}
+	assert.Equal(
+		[][]Segment{
+			{{Text: "a "}, {Text: "well-", Style: red}},
+			{{Text: "known", Style: red}, {Text: " word"}},
+			{{Text: "unbreakabl"}},
+			{{Text: "e"}},
+		},
+		Wrap(Parse("a <fg=red>well-known</> word unbreakable"), 10),
 	)
+	assert.Equal([][]Segment{{{Text: "a"}}, {{Text: "b"}}}, Wrap(Parse("a\nb"), 0))
 }
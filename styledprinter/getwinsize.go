@@ -1,15 +1,20 @@
 package styledprinter
 
 import (
-	"os"
+	"io"
 
 	"golang.org/x/term"
 )
 
-// GetWinsize return the size (width, height) of the current terminal window
-func getWinsize() (int, int) {
-	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+// GetWinsize return the size (width, height) of the terminal behind the given writer.
+// If the writer is not a terminal, a default size is returned.
+func getWinsize(out io.Writer) (int, int) {
+	file, ok := out.(interface{ Fd() uintptr })
+	if !ok {
+		return 120, 60
+	}
 
+	width, height, err := term.GetSize(int(file.Fd()))
 	if err != nil {
 		return 120, 60
 	}
@@ -0,0 +1,12 @@
+package styledprinter
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain forces the color level, so that the escape sequences do not depend on the terminal running the tests
+func TestMain(m *testing.M) {
+	SetColorLevel(ColorLevel16)
+	os.Exit(m.Run())
+}
@@ -22,6 +22,15 @@ var (
 		"cyan":    [2]string{"36", "39"},
 		"white":   [2]string{"37", "39"},
 		"default": [2]string{"39", "39"},
+
+		"gray":           [2]string{"90", "39"},
+		"bright-red":     [2]string{"91", "39"},
+		"bright-green":   [2]string{"92", "39"},
+		"bright-yellow":  [2]string{"93", "39"},
+		"bright-blue":    [2]string{"94", "39"},
+		"bright-magenta": [2]string{"95", "39"},
+		"bright-cyan":    [2]string{"96", "39"},
+		"bright-white":   [2]string{"97", "39"},
 	}
 	availableBackgroundColors = map[string]([2]string){
 		"black":   [2]string{"40", "49"},
@@ -33,6 +42,15 @@ var (
 		"cyan":    [2]string{"46", "49"},
 		"white":   [2]string{"47", "49"},
 		"default": [2]string{"49", "49"},
+
+		"gray":           [2]string{"100", "49"},
+		"bright-red":     [2]string{"101", "49"},
+		"bright-green":   [2]string{"102", "49"},
+		"bright-yellow":  [2]string{"103", "49"},
+		"bright-blue":    [2]string{"104", "49"},
+		"bright-magenta": [2]string{"105", "49"},
+		"bright-cyan":    [2]string{"106", "49"},
+		"bright-white":   [2]string{"107", "49"},
 	}
 	availableOptions = map[string]([2]string){
 		"bold":       [2]string{"1", "22"},
@@ -40,6 +58,12 @@ var (
 		"blink":      [2]string{"5", "25"},
 		"reverse":    [2]string{"7", "27"},
 		"conceal":    [2]string{"8", "28"},
+
+		"dim":              [2]string{"2", "22"},
+		"italic":           [2]string{"3", "23"},
+		"strikethrough":    [2]string{"9", "29"},
+		"double-underline": [2]string{"21", "24"},
+		"overline":         [2]string{"53", "55"},
 	}
 	styleRegexp     = regexp.MustCompile(`([^=]+)=([^;]+)(;|$)`)
 	separatorRegexp = regexp.MustCompile(`([^,;]+)`)
@@ -102,16 +126,26 @@ func NewOutputStyle(styleString string) *OutputStyle {
 	}
 }
 
-// Apply surrounds a given string with the adequate ANSI escape sequence.
+// Apply surrounds a given string with the adequate ANSI escape sequence, using the color level of the standard output.
 func (s OutputStyle) Apply(text string) string {
+	return s.ApplyWithLevel(text, defaultPrinter.ColorLevel())
+}
+
+// ApplyWithLevel surrounds a given string with the ANSI escape sequence, using colors compatible with the given level.
+// With ColorLevelNone, the text is returned as-is.
+func (s OutputStyle) ApplyWithLevel(text string, level ColorLevel) string {
+	if level == ColorLevelNone {
+		return text
+	}
+
 	var setCodes []string
 	var unsetCodes []string
 
-	if foreground, ok := availableForegroundColors[s.foreground]; ok {
+	if foreground, ok := getColorCodes(s.foreground, false, level); ok {
 		setCodes = append(setCodes, foreground[0])
 		unsetCodes = append(unsetCodes, foreground[1])
 	}
-	if background, ok := availableBackgroundColors[s.background]; ok {
+	if background, ok := getColorCodes(s.background, true, level); ok {
 		setCodes = append(setCodes, background[0])
 		unsetCodes = append(unsetCodes, background[1])
 	}
@@ -134,5 +168,14 @@ func (s OutputStyle) Apply(text string) string {
 	sort.Strings(setCodes)
 	sort.Strings(unsetCodes)
 
+	// Some options share the same unset code (bold and dim for instance), it is only needed once
+	uniqueUnsetCodes := unsetCodes[:1]
+	for _, code := range unsetCodes[1:] {
+		if code != uniqueUnsetCodes[len(uniqueUnsetCodes)-1] {
+			uniqueUnsetCodes = append(uniqueUnsetCodes, code)
+		}
+	}
+	unsetCodes = uniqueUnsetCodes
+
 	return fmt.Sprintf("\033[%sm%s\033[%sm", strings.Join(setCodes, ";"), text, strings.Join(unsetCodes, ";"))
 }
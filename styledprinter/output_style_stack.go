@@ -7,6 +7,7 @@ type outputStyleStack struct {
 	styles          []OutputStyle
 }
 
+// newOutputStyleStack creates a stack whose styles extend the given base style
 func newOutputStyleStack(baseStyleString string) outputStyleStack {
 	if baseStyleString != "" {
 		baseStyle := NewOutputStyle(baseStyleString)
@@ -45,8 +46,9 @@ func (s *outputStyleStack) Pop(oldStyleString string) bool {
 		return false
 	}
 
-	for i := len(s.styles) - 1; i > 0; i-- {
-		if s.styles[i].Apply(``) == oldStyle.Apply(``) {
+	// The styles are compared with the escape sequences they produce, whatever the colors supported by the output
+	for i := len(s.styles) - 1; i >= 0; i-- {
+		if s.styles[i].ApplyWithLevel(``, ColorLevelTrueColor) == oldStyle.ApplyWithLevel(``, ColorLevelTrueColor) {
 			s.styles = s.styles[:i]
 			return true
 		}
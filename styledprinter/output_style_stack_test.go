@@ -59,3 +59,16 @@ func TestBaseStyle(t *testing.T) {
 	stack.PopCurrent()
 	assert.Equal(OutputStyle{background: "green", foreground: "blue", handleHrefGracefully: true}, stack.GetCurrent())
 }
+
+// TestPopFirstStyle checks the first style of the stack can be closed by name
+func TestPopFirstStyle(t *testing.T) {
+	assert := assert.New(t)
+	stack := newOutputStyleStack("")
+
+	stack.Push("fg=green")
+	stack.Push("fg=blue")
+
+	assert.True(stack.Pop("fg=green"))
+	assert.Equal(OutputStyle{}, stack.GetCurrent())
+	assert.False(stack.Pop("fg=green"))
+}
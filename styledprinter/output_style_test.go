@@ -88,3 +88,17 @@ func TestMergeStyles(t *testing.T) {
 		NewOutputStyle(firstStyle+";"+bgStyle),
 	)
 }
+
+// TestApplyBrightColorsAndOptions checks the bright colors and additional options
+func TestApplyBrightColorsAndOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	mystyle := NewOutputStyle("fg=bright-red;bg=gray")
+	assert.Equal("\033[100;91mThis is a text.\033[39;49m", mystyle.Apply("This is a text."))
+
+	mystyle = NewOutputStyle("options=bold,dim,italic,strikethrough")
+	assert.Equal("\033[1;2;3;9mThis is a text.\033[22;23;29m", mystyle.Apply("This is a text."))
+
+	mystyle = NewOutputStyle("options=double-underline,overline")
+	assert.Equal("\033[21;53mThis is a text.\033[24;55m", mystyle.Apply("This is a text."))
+}
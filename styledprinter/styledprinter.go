@@ -3,43 +3,135 @@ package styledprinter
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 )
 
+var defaultPrinter = NewPrinter(os.Stdout)
+
+// Printer prints styled text to a given io.Writer, wrapping it to the width of the terminal
+type Printer struct {
+	out   io.Writer
+	width int
+	level ColorLevel
+}
+
+// NewPrinter instanciates a Printer that writes to the given io.Writer.
+// If the writer is a terminal, its size is used to wrap the text, otherwise a default width is used.
+// The colors are adapted to what the writer supports, see DetectColorLevel().
+func NewPrinter(out io.Writer) *Printer {
+	return &Printer{out: out, level: DetectColorLevel(out)}
+}
+
+// SetColorLevel overrides the detected range of colors that the printer can use.
+func (p *Printer) SetColorLevel(level ColorLevel) {
+	p.level = level
+}
+
+// ColorLevel returns the range of colors that the printer can use
+func (p *Printer) ColorLevel() ColorLevel {
+	return p.level
+}
+
+// SetWidth forces the width used to wrap the text. A width of 0 restores the detection from the terminal.
+func (p *Printer) SetWidth(width int) {
+	p.width = width
+}
+
+// Width returns the width used to wrap the text
+func (p *Printer) Width() int {
+	if p.width > 0 {
+		return p.width
+	}
+
+	width, _ := getWinsize(p.out)
+	return width
+}
+
+// Format replaces the style tags of a text with escape sequences, and splits it in lines of the given width, without printing it.
+// If width is not positive, the width of the printer is used.
+func (p *Printer) Format(text string, width int) []string {
+	if width <= 0 {
+		width = p.Width()
+	}
+
+	return formatTextWithLevel(text, width, 0, "", p.level)
+}
+
 // WriteBlock prints a block of text using a string padding, with optionnal styles
-func WriteBlock(message string, padding string, baseStyle string, newLine bool) {
-	width, _ := getWinsize()
+func (p *Printer) WriteBlock(message string, padding string, baseStyle string, newLine bool) {
+	width := p.Width()
 
-	widthWithoutPadding := width - len(padding)
+	widthWithoutPadding := width - StringWidth(padding)
 	extractedBaseStyle := NewOutputStyle(baseStyle)
+	if extractedBaseStyle == nil {
+		extractedBaseStyle = &OutputStyle{}
+	}
 
 	// We ensure there is a last line at the end to have the background everywhere
 	if message[len(message)-1:] != "\n" {
 		message = message + "\n"
 	}
 
-	formattedLines := formatText(message, widthWithoutPadding, baseStyle)
-	emptyLine := extractedBaseStyle.Apply(padding + strings.Repeat(" ", widthWithoutPadding))
+	formattedLines := formatTextWithLevel(message, widthWithoutPadding, 0, baseStyle, p.level)
+	emptyLine := extractedBaseStyle.ApplyWithLevel(padding+strings.Repeat(" ", widthWithoutPadding), p.level)
 
 	// However, we remove the last empty line, to blend with the block
-	fmt.Printf("%s\n", emptyLine)
+	fmt.Fprintf(p.out, "%s\n", emptyLine)
 	for _, line := range formattedLines[0 : len(formattedLines)-1] {
-		fmt.Printf("%s%s\n", extractedBaseStyle.Apply(padding), line)
+		fmt.Fprintf(p.out, "%s%s\n", extractedBaseStyle.ApplyWithLevel(padding, p.level), line)
 	}
-	fmt.Printf("%s\n", emptyLine)
+	fmt.Fprintf(p.out, "%s\n", emptyLine)
 
 	if newLine {
-		fmt.Printf("\n")
+		fmt.Fprintf(p.out, "\n")
 	}
 }
 
 // Write prints a list of messages, one per line, with an optionnal end-of-line at the end
-func Write(message string, newLine bool) {
-	width, _ := getWinsize()
+func (p *Printer) Write(message string, newLine bool) {
+	p.WriteIndented(message, 0, newLine)
+}
 
-	formattedLines := formatText(message, width, "")
-	fmt.Print(strings.Join(formattedLines, "\n"))
+// WriteIndented prints a message like Write, but the lines that are wrapped are indented with the given amount of columns
+func (p *Printer) WriteIndented(message string, indent int, newLine bool) {
+	formattedLines := formatTextWithLevel(message, p.Width(), indent, "", p.level)
+	fmt.Fprint(p.out, strings.Join(formattedLines, "\n"))
 	if newLine {
-		fmt.Print("\n")
+		fmt.Fprint(p.out, "\n")
 	}
 }
+
+// Format replaces the style tags of a text with escape sequences suitable for the standard output,
+// and splits it in lines of the given width, without printing it.
+// If width is not positive, the width of the standard output is used.
+func Format(text string, width int) []string {
+	return defaultPrinter.Format(text, width)
+}
+
+// Strip removes all the style tags from a text, and returns the text as it would be displayed.
+// Invalid tags are kept, as they would be printed as-is.
+func Strip(text string) string {
+	return stripTags(text)
+}
+
+// WriteBlock prints a block of text on the standard output using a string padding, with optionnal styles
+func WriteBlock(message string, padding string, baseStyle string, newLine bool) {
+	defaultPrinter.WriteBlock(message, padding, baseStyle, newLine)
+}
+
+// Write prints a list of messages on the standard output, one per line, with an optionnal end-of-line at the end
+func Write(message string, newLine bool) {
+	defaultPrinter.Write(message, newLine)
+}
+
+// WriteIndented prints a message on the standard output like Write, but the lines that are wrapped are indented with the given amount of columns
+func WriteIndented(message string, indent int, newLine bool) {
+	defaultPrinter.WriteIndented(message, indent, newLine)
+}
+
+// SetColorLevel overrides the detected range of colors that can be used on the standard output.
+func SetColorLevel(level ColorLevel) {
+	defaultPrinter.SetColorLevel(level)
+}
@@ -0,0 +1,60 @@
+package styledprinter
+
+import (
+	"github.com/rivo/uniseg"
+)
+
+// StringWidth returns the number of columns needed to display a text in a terminal.
+// Grapheme clusters (emojis, letters with combining accents...) are measured as a whole, and East Asian wide characters take two columns.
+// The text should not contain style tags, use Width() otherwise.
+func StringWidth(text string) int {
+	return uniseg.StringWidth(text)
+}
+
+// Width returns the number of columns needed to display a text containing style tags.
+func Width(text string) int {
+	return StringWidth(Strip(text))
+}
+
+// SplitAtWidth splits a text in two parts, the first one being as long as possible without exceeding the given width.
+// Grapheme clusters are never split, so the first part can be narrower than width. However, if the first grapheme cluster
+// is wider than width, it is returned alone as the first part, so that a text can always be split in lines.
+func SplitAtWidth(text string, width int) (string, string) {
+	head, rest := splitAtWidth(text, width)
+	if head == `` && rest != `` {
+		cluster, rest, _, _ := uniseg.FirstGraphemeClusterInString(rest, -1)
+		return cluster, rest
+	}
+
+	return head, rest
+}
+
+// Truncate shortens a text so that it does not exceed the given width, and ends it with the tail if it was shortened.
+// The width of the tail is included in the given width.
+func Truncate(text string, width int, tail string) string {
+	if StringWidth(text) <= width {
+		return text
+	}
+
+	head, _ := splitAtWidth(text, width-StringWidth(tail))
+	return head + tail
+}
+
+// splitAtWidth is the same as SplitAtWidth, but the first part never exceeds the given width, even if it has to be empty.
+func splitAtWidth(text string, width int) (string, string) {
+	headWidth := 0
+	rest := text
+	state := -1
+	for rest != `` {
+		_, newRest, clusterWidth, newState := uniseg.FirstGraphemeClusterInString(rest, state)
+		if headWidth+clusterWidth > width {
+			break
+		}
+
+		headWidth += clusterWidth
+		rest = newRest
+		state = newState
+	}
+
+	return text[:len(text)-len(rest)], rest
+}
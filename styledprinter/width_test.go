@@ -0,0 +1,59 @@
+package styledprinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStringWidth checks the width of wide characters, emojis and combining accents
+func TestStringWidth(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(5, StringWidth("hello"))
+	assert.Equal(5, StringWidth("troïs"))
+	assert.Equal(5, StringWidth("troïs"))
+	assert.Equal(4, StringWidth("日本"))
+	assert.Equal(2, StringWidth("👍"))
+	assert.Equal(2, StringWidth("👨‍👩‍👧"))
+	assert.Equal(4, Width("<fg=red>日本</>"))
+}
+
+// TestSplitAtWidth checks a text can be split without breaking grapheme clusters
+func TestSplitAtWidth(t *testing.T) {
+	assert := assert.New(t)
+
+	head, rest := SplitAtWidth("zaaaz", 3)
+	assert.Equal("zaa", head)
+	assert.Equal("az", rest)
+
+	head, rest = SplitAtWidth("zaaaz", 50)
+	assert.Equal("zaaaz", head)
+	assert.Equal("", rest)
+
+	head, rest = SplitAtWidth("日本語", 3)
+	assert.Equal("日", head)
+	assert.Equal("本語", rest)
+
+	head, rest = SplitAtWidth("troïs", 4)
+	assert.Equal("troï", head)
+	assert.Equal("s", rest)
+
+	head, rest = SplitAtWidth("日本語", 1)
+	assert.Equal("日", head)
+	assert.Equal("本語", rest)
+
+	head, rest = splitAtWidth("日本語", 1)
+	assert.Equal("", head)
+	assert.Equal("日本語", rest)
+}
+
+// TestTruncate checks a text can be shortened to a given width
+func TestTruncate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("short", Truncate("short", 5, "…"))
+	assert.Equal("shor…", Truncate("shorter", 5, "…"))
+	assert.Equal("日本…", Truncate("日本語です", 6, "…"))
+	assert.Equal("日…", Truncate("日本語です", 4, "…"))
+}
@@ -0,0 +1,248 @@
+package styledprinter
+
+import (
+	"strings"
+)
+
+// linePiece is a part of a line of text, with the index of the segment it comes from
+type linePiece struct {
+	text    string
+	segment int
+}
+
+// wrappedLine is a line of text built by the wrapper
+type wrappedLine struct {
+	pieces []linePiece
+	width  int
+	// padSegment is the segment whose style is used to fill the end of the line with spaces
+	padSegment int
+}
+
+// append adds some text at the end of the line, merging it with the last piece if it comes from the same segment
+func (l *wrappedLine) append(text string, segment int, width int) {
+	l.pieces = appendPiece(l.pieces, text, segment)
+	l.width += width
+}
+
+func appendPiece(pieces []linePiece, text string, segment int) []linePiece {
+	if len(pieces) > 0 && pieces[len(pieces)-1].segment == segment {
+		pieces[len(pieces)-1].text += text
+		return pieces
+	}
+
+	return append(pieces, linePiece{text: text, segment: segment})
+}
+
+// Wrap splits the segments in lines that fit in the given width, like the texts printed by Format().
+// The lines are broken on spaces and after hyphens, and each piece of a line keeps the style of the segment it comes from.
+func Wrap(segments []Segment, width int) [][]Segment {
+	if width < 1 {
+		width = 1
+	}
+
+	lines := wrapSegments(segments, width, 0)
+	wrapped := make([][]Segment, len(lines))
+	for i, line := range lines {
+		for _, piece := range line.pieces {
+			wrapped[i] = append(wrapped[i], Segment{Text: piece.text, Style: segments[piece.segment].Style})
+		}
+	}
+
+	return wrapped
+}
+
+// wrapper splits a list of segments in lines that fit in a given width. The lines are broken on spaces and after hyphens,
+// and the words that are longer than a whole line are split wherever necessary.
+type wrapper struct {
+	width  int
+	indent int
+
+	lines       []wrappedLine
+	spaces      []linePiece
+	spacesWidth int
+	word        []linePiece
+	wordWidth   int
+}
+
+// wrapSegments splits the segments in lines that fit in the given width.
+// Every line but the first is indented with the given amount of columns, in order to have a hanging indent.
+func wrapSegments(segments []Segment, width int, indent int) []wrappedLine {
+	if indent >= width {
+		indent = 0
+	}
+
+	w := &wrapper{width: width, indent: indent, lines: []wrappedLine{{}}}
+	for i, segment := range segments {
+		w.write(lineEndRegexp.ReplaceAllString(segment.Text, "\n"), i)
+	}
+	w.flushWord()
+	w.flushSpaces()
+
+	return w.lines
+}
+
+// write splits a text in words, spaces and line breaks
+func (w *wrapper) write(text string, segment int) {
+	for text != `` {
+		switch text[0] {
+		case '\n':
+			w.flushWord()
+			w.flushSpaces()
+			w.newLine(segment)
+			text = text[1:]
+		case ' ':
+			w.flushWord()
+			w.spaces = appendPiece(w.spaces, " ", segment)
+			w.spacesWidth++
+			text = text[1:]
+		default:
+			end := strings.IndexAny(text, " \n")
+			if end < 0 {
+				end = len(text)
+			}
+
+			chunk := text[:end]
+			breakIndex := hyphenBreakIndex(chunk, w.wordWidth > 0)
+			if breakIndex > 0 {
+				chunk = chunk[:breakIndex]
+			}
+
+			w.word = appendPiece(w.word, chunk, segment)
+			w.wordWidth += StringWidth(chunk)
+			text = text[len(chunk):]
+
+			if breakIndex > 0 {
+				// The line can be broken after the hyphen
+				w.flushWord()
+			}
+		}
+	}
+}
+
+// hyphenBreakIndex returns the position after the first hyphen where a word can be broken, or -1.
+// A word can be broken after a hyphen that is surrounded by other characters, like in "well-known".
+func hyphenBreakIndex(chunk string, hasPrefix bool) int {
+	for i := 0; i < len(chunk)-1; i++ {
+		if chunk[i] != '-' || chunk[i+1] == '-' {
+			continue
+		}
+		if (i == 0 && hasPrefix) || (i > 0 && chunk[i-1] != '-') {
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// availableWidth returns the width available on the current line
+func (w *wrapper) availableWidth() int {
+	if len(w.lines) > 1 {
+		return w.width - w.indent
+	}
+
+	return w.width
+}
+
+func (w *wrapper) currentLine() *wrappedLine {
+	return &w.lines[len(w.lines)-1]
+}
+
+// newLine ends the current line, and fills its end with the style of the given segment
+func (w *wrapper) newLine(padSegment int) {
+	w.currentLine().padSegment = padSegment
+	w.lines = append(w.lines, wrappedLine{})
+}
+
+// flushWord adds the pending word to the lines, preceded by the pending spaces if they fit on the current line
+func (w *wrapper) flushWord() {
+	if len(w.word) == 0 {
+		return
+	}
+
+	line := w.currentLine()
+	if line.width+w.spacesWidth+w.wordWidth <= w.availableWidth() {
+		for _, piece := range w.spaces {
+			line.append(piece.text, piece.segment, StringWidth(piece.text))
+		}
+		for _, piece := range w.word {
+			line.append(piece.text, piece.segment, StringWidth(piece.text))
+		}
+	} else {
+		if line.width > 0 {
+			// The spaces are replaced by the line break
+			padSegment := line.pieces[len(line.pieces)-1].segment
+			if len(w.spaces) > 0 {
+				padSegment = w.spaces[0].segment
+			}
+			w.newLine(padSegment)
+		}
+
+		w.splitWord()
+	}
+
+	w.spaces, w.spacesWidth = nil, 0
+	w.word, w.wordWidth = nil, 0
+}
+
+// splitWord adds the pending word on as many lines as required
+func (w *wrapper) splitWord() {
+	for _, piece := range w.word {
+		text := piece.text
+		for text != `` {
+			line := w.currentLine()
+			head, rest := SplitAtWidth(text, w.availableWidth()-line.width)
+			headWidth := StringWidth(head)
+			if line.width > 0 && line.width+headWidth > w.availableWidth() {
+				w.newLine(piece.segment)
+				continue
+			}
+
+			line.append(head, piece.segment, headWidth)
+			text = rest
+			if text != `` {
+				w.newLine(piece.segment)
+			}
+		}
+	}
+}
+
+// flushSpaces adds the pending spaces to the current line if they fit, they are dropped otherwise
+func (w *wrapper) flushSpaces() {
+	line := w.currentLine()
+	if line.width+w.spacesWidth <= w.availableWidth() {
+		for _, piece := range w.spaces {
+			line.append(piece.text, piece.segment, StringWidth(piece.text))
+		}
+	}
+
+	w.spaces, w.spacesWidth = nil, 0
+}
+
+// renderLines applies the styles of the segments to the lines. Every line but the last is filled with spaces up to the given width.
+func renderLines(lines []wrappedLine, segments []Segment, width int, indent int, level ColorLevel) []string {
+	if indent >= width {
+		indent = 0
+	}
+
+	output := make([]string, len(lines))
+	for i, line := range lines {
+		var rendered strings.Builder
+		lineWidth := line.width
+		if i > 0 && indent > 0 {
+			rendered.WriteString(strings.Repeat(" ", indent))
+			lineWidth += indent
+		}
+
+		pieces := line.pieces
+		if i < len(lines)-1 && lineWidth < width {
+			pieces = appendPiece(pieces, strings.Repeat(" ", width-lineWidth), line.padSegment)
+		}
+
+		for _, piece := range pieces {
+			rendered.WriteString(segments[piece.segment].Style.ApplyWithLevel(piece.text, level))
+		}
+		output[i] = rendered.String()
+	}
+
+	return output
+}
@@ -1,11 +1,170 @@
 package styledconsole
 
 import (
-	"fmt"
 	"strings"
-	"unicode/utf8"
+
+	"github.com/corentindeboisset/styledconsole/styledprinter"
+)
+
+// Alignment is the horizontal alignment of the content of a table column
+type Alignment int
+
+const (
+	// AlignLeft aligns the content of the column on its left border
+	AlignLeft Alignment = iota
+	// AlignRight aligns the content of the column on its right border
+	AlignRight
+	// AlignCenter centers the content of the column
+	AlignCenter
+)
+
+// ColumnOptions customizes the rendering of a table column
+type ColumnOptions struct {
+	// Align is the alignment of the cells of the column
+	Align Alignment
+	// MinWidth is the minimal width of the column, even if its content is smaller
+	MinWidth int
+	// MaxWidth is the maximal width of the column, longer cells are split on several lines. 0 means no limit.
+	MaxWidth int
+}
+
+// TableLine is an horizontal line of a table, drawn with a character for each border and crossing
+type TableLine struct {
+	Left  string
+	Fill  string
+	Cross string
+	Right string
+}
+
+// TableStyle defines the characters used to draw the borders of a table
+type TableStyle struct {
+	// Top, HeaderSeparator and Bottom are the horizontal lines of the table. Empty lines are not printed.
+	Top             TableLine
+	HeaderSeparator TableLine
+	Bottom          TableLine
+	// Left, Separator and Right are the vertical lines of the table
+	Left      string
+	Separator string
+	Right     string
+	// Padding is the count of spaces on each side of the content of a cell
+	Padding int
+
+	markdown bool
+}
+
+var (
+	// TableStyleDefault draws the borders with ASCII characters
+	TableStyleDefault = TableStyle{
+		Top:             TableLine{"+", "-", "+", "+"},
+		HeaderSeparator: TableLine{"+", "-", "+", "+"},
+		Bottom:          TableLine{"+", "-", "+", "+"},
+		Left:            "|",
+		Separator:       "|",
+		Right:           "|",
+		Padding:         1,
+	}
+	// TableStyleBox draws the borders with box-drawing characters
+	TableStyleBox = TableStyle{
+		Top:             TableLine{"┌", "─", "┬", "┐"},
+		HeaderSeparator: TableLine{"├", "─", "┼", "┤"},
+		Bottom:          TableLine{"└", "─", "┴", "┘"},
+		Left:            "│",
+		Separator:       "│",
+		Right:           "│",
+		Padding:         1,
+	}
+	// TableStyleDouble draws the borders with double-line box-drawing characters
+	TableStyleDouble = TableStyle{
+		Top:             TableLine{"╔", "═", "╦", "╗"},
+		HeaderSeparator: TableLine{"╠", "═", "╬", "╣"},
+		Bottom:          TableLine{"╚", "═", "╩", "╝"},
+		Left:            "║",
+		Separator:       "║",
+		Right:           "║",
+		Padding:         1,
+	}
+	// TableStyleRounded draws the borders with box-drawing characters and rounded corners
+	TableStyleRounded = TableStyle{
+		Top:             TableLine{"╭", "─", "┬", "╮"},
+		HeaderSeparator: TableLine{"├", "─", "┼", "┤"},
+		Bottom:          TableLine{"╰", "─", "┴", "╯"},
+		Left:            "│",
+		Separator:       "│",
+		Right:           "│",
+		Padding:         1,
+	}
+	// TableStyleCompact only underlines the headers, and separates the columns with spaces
+	TableStyleCompact = TableStyle{
+		HeaderSeparator: TableLine{"", "-", "  ", ""},
+		Separator:       "  ",
+	}
+	// TableStyleBorderless draws horizontal lines with "=", without vertical lines
+	TableStyleBorderless = TableStyle{
+		Top:             TableLine{" ", "=", " ", " "},
+		HeaderSeparator: TableLine{" ", "=", " ", " "},
+		Bottom:          TableLine{" ", "=", " ", " "},
+		Left:            " ",
+		Separator:       " ",
+		Right:           " ",
+		Padding:         1,
+	}
+	// TableStyleMarkdown renders a GitHub-flavored markdown table. Style tags are removed from the cells,
+	// line breaks are replaced with "<br>" and the cells are never wrapped.
+	TableStyleMarkdown = TableStyle{
+		Left:      "|",
+		Separator: "|",
+		Right:     "|",
+		Padding:   1,
+		markdown:  true,
+	}
 )
 
+// TableOptions customizes the rendering of a table
+type TableOptions struct {
+	// Columns are the options of each column, by index. Columns without options use the default ones.
+	Columns []ColumnOptions
+	// Style defines the characters used to draw the borders. It defaults to TableStyleDefault.
+	Style TableStyle
+}
+
+// style returns the style of the table, or the default one
+func (o TableOptions) style() TableStyle {
+	if o.Style == (TableStyle{}) {
+		return TableStyleDefault
+	}
+
+	return o.Style
+}
+
+// column returns the options of the column at the given index
+func (o TableOptions) column(index int) ColumnOptions {
+	if index < len(o.Columns) {
+		return o.Columns[index]
+	}
+
+	return ColumnOptions{}
+}
+
+// tableLayout holds what is needed to render the rows of a table
+type tableLayout struct {
+	columnWidths []int
+	options      TableOptions
+	colorLevel   styledprinter.ColorLevel
+}
+
+// getCellWidth returns the width of the largest line of a cell, without its style tags
+func getCellWidth(cell string) int {
+	cellWidth := 0
+	for _, line := range strings.Split(styledprinter.Strip(cell), "\n") {
+		lineLen := styledprinter.StringWidth(line)
+		if cellWidth < lineLen {
+			cellWidth = lineLen
+		}
+	}
+
+	return cellWidth
+}
+
 func getColumnWidths(headers []string, content [][]string) []int {
 	columnCount := len(headers)
 	for _, row := range content {
@@ -17,24 +176,11 @@ func getColumnWidths(headers []string, content [][]string) []int {
 	var columnWidths = make([]int, columnCount)
 
 	for i, headerItem := range headers {
-		itemWidth := 0
-		for _, line := range strings.Split(headerItem, "\n") {
-			lineLen := utf8.RuneCountInString(line)
-			if itemWidth < lineLen {
-				itemWidth = lineLen
-			}
-		}
-		columnWidths[i] = itemWidth
+		columnWidths[i] = getCellWidth(headerItem)
 	}
 	for _, row := range content {
 		for i, rowItem := range row {
-			itemWidth := 0
-			for _, line := range strings.Split(rowItem, "\n") {
-				lineLen := utf8.RuneCountInString(line)
-				if itemWidth < lineLen {
-					itemWidth = lineLen
-				}
-			}
+			itemWidth := getCellWidth(rowItem)
 			if columnWidths[i] < itemWidth {
 				columnWidths[i] = itemWidth
 			}
@@ -44,44 +190,71 @@ func getColumnWidths(headers []string, content [][]string) []int {
 	return columnWidths
 }
 
-func getTableWidth(widths []int) int {
+// applyColumnLimits forces the width of the columns between their minimal and maximal width
+func applyColumnLimits(columnWidths []int, options TableOptions) []int {
+	limitedWidths := make([]int, len(columnWidths))
+	for i, width := range columnWidths {
+		column := options.column(i)
+		if column.MaxWidth > 0 && width > column.MaxWidth {
+			width = column.MaxWidth
+		}
+		if width < column.MinWidth {
+			width = column.MinWidth
+		}
+		limitedWidths[i] = width
+	}
+
+	return limitedWidths
+}
+
+func getTableWidth(widths []int, style TableStyle) int {
 	totalWidth := 0
 	for _, width := range widths {
-		totalWidth += width
+		totalWidth += width + 2*style.Padding
 	}
-	totalWidth += 4 + 3*(len(widths)-1)
+	totalWidth += styledprinter.StringWidth(style.Left) + styledprinter.StringWidth(style.Right)
+	totalWidth += styledprinter.StringWidth(style.Separator) * (len(widths) - 1)
 
 	return totalWidth
 }
 
-func getAcceptableColumnWidths(startingWidths []int, termWidth int) []int {
+func getAcceptableColumnWidths(startingWidths []int, minWidths []int, termWidth int, style TableStyle) []int {
 	// Deep copy of startingWidths
 	columnWidths := make([]int, len(startingWidths))
 	copy(columnWidths, startingWidths)
 
+	// A column is never reduced under 15 chars, or under its minimal width
+	reducedWidths := make([]int, len(columnWidths))
+	for i := range reducedWidths {
+		reducedWidths[i] = 15
+		if i < len(minWidths) && minWidths[i] > reducedWidths[i] {
+			reducedWidths[i] = minWidths[i]
+		}
+	}
+
 	for {
-		if getTableWidth(columnWidths) < termWidth {
+		if getTableWidth(columnWidths, style) < termWidth {
 			// It fits, we can stop
 			return columnWidths
 		}
 
 		// Every time, we try to reduce the largest column
-		largestIdx := 0
+		largestIdx := -1
 		largestWidth := 0
 		for i, wid := range columnWidths {
-			if largestWidth < wid {
+			if largestWidth < wid && wid > reducedWidths[i] {
 				largestWidth = wid
 				largestIdx = i
 			}
 		}
-		if largestWidth > 15 {
-			if getTableWidth(columnWidths)-termWidth < largestWidth-15 {
+		if largestIdx >= 0 {
+			if getTableWidth(columnWidths, style)-termWidth < largestWidth-reducedWidths[largestIdx] {
 				// If we reduce the largest column, it will fit
-				columnWidths[largestIdx] -= getTableWidth(columnWidths) - termWidth
+				columnWidths[largestIdx] -= getTableWidth(columnWidths, style) - termWidth
 				return columnWidths
 			} else {
-				// We reduce the largest column to 15char, it won't fit so we continue
-				columnWidths[largestIdx] = 15
+				// We reduce the largest column as much as possible, it won't fit so we continue
+				columnWidths[largestIdx] = reducedWidths[largestIdx]
 				continue
 			}
 		}
@@ -91,6 +264,9 @@ func getAcceptableColumnWidths(startingWidths []int, termWidth int) []int {
 		if avgWidth >= 10 {
 			for i := range columnWidths {
 				columnWidths[i] = avgWidth
+				if i < len(minWidths) && minWidths[i] > avgWidth {
+					columnWidths[i] = minWidths[i]
+				}
 			}
 		}
 
@@ -99,47 +275,184 @@ func getAcceptableColumnWidths(startingWidths []int, termWidth int) []int {
 	}
 }
 
-func formatOneRow(row []string, columnWidths []int) string {
-	var preparedSubLines [][]string
-	totalLines := 1
+// wrapCell splits the content of a cell in lines that are not wider than the given width, breaking them between the words
+func wrapCell(cell string, width int) [][]styledprinter.Segment {
+	return styledprinter.Wrap(styledprinter.Parse(cell), width)
+}
 
-	for cellIdx, cell := range row {
-		var preparedCellLines []string
-		for _, subLine := range strings.Split(cell, "\n") {
-			subLineLen := utf8.RuneCountInString(subLine)
-			if subLineLen <= columnWidths[cellIdx] {
-				preparedCellLines = append(preparedCellLines, subLine)
-			} else {
-				i := 0
-				for {
-					if i+columnWidths[cellIdx] > subLineLen {
-						preparedCellLines = append(preparedCellLines, subLine[i:])
-						break
-					} else {
-						preparedCellLines = append(preparedCellLines, subLine[i:i+columnWidths[cellIdx]])
-						i += columnWidths[cellIdx]
-					}
-				}
+// alignCellLine renders a line of a cell and pads it with spaces to fill the column
+func (t tableLayout) alignCellLine(line []styledprinter.Segment, columnIdx int) string {
+	lineWidth := 0
+	renderedLine := ""
+	for _, segment := range line {
+		lineWidth += styledprinter.StringWidth(segment.Text)
+		renderedLine += segment.Style.ApplyWithLevel(segment.Text, t.colorLevel)
+	}
+
+	padding := t.columnWidths[columnIdx] - lineWidth
+	if padding < 0 {
+		padding = 0
+	}
+
+	switch t.options.column(columnIdx).Align {
+	case AlignRight:
+		return strings.Repeat(" ", padding) + renderedLine
+	case AlignCenter:
+		return strings.Repeat(" ", padding/2) + renderedLine + strings.Repeat(" ", padding-padding/2)
+	default:
+		return renderedLine + strings.Repeat(" ", padding)
+	}
+}
+
+// formatTable renders a table with its borders, adapted to the width of the terminal
+func formatTable(headers []string, rows [][]string, options TableOptions, termWidth int, colorLevel styledprinter.ColorLevel) string {
+	style := options.style()
+
+	if style.markdown {
+		// Markdown cells are plain text on a single line
+		headers = toMarkdownCells(headers)
+		markdownRows := make([][]string, len(rows))
+		for i, row := range rows {
+			markdownRows[i] = toMarkdownCells(row)
+		}
+		rows = markdownRows
+		colorLevel = styledprinter.ColorLevelNone
+	}
+
+	// First we have to determinate the width of every column
+	columnWidths := getColumnWidths(headers, rows)
+
+	minWidths := make([]int, len(columnWidths))
+	for i := range minWidths {
+		minWidths[i] = options.column(i).MinWidth
+	}
+
+	if style.markdown {
+		for i, width := range columnWidths {
+			// The separator of the headers needs at least 3 characters
+			if width+2*style.Padding < 3 {
+				width = 3 - 2*style.Padding
 			}
+			if width < minWidths[i] {
+				width = minWidths[i]
+			}
+			columnWidths[i] = width
+		}
+	} else {
+		columnWidths = getAcceptableColumnWidths(applyColumnLimits(columnWidths, options), minWidths, termWidth, style)
+	}
+
+	layout := tableLayout{
+		columnWidths: columnWidths,
+		options:      options,
+		colorLevel:   colorLevel,
+	}
+
+	var formattedRows []string
+	if top := layout.formatLine(style.Top); top != "" {
+		formattedRows = append(formattedRows, top)
+	}
+	formattedRows = append(formattedRows, layout.formatOneRow(headers))
+	if style.markdown {
+		formattedRows = append(formattedRows, layout.formatMarkdownSeparator())
+	} else if separator := layout.formatLine(style.HeaderSeparator); separator != "" {
+		formattedRows = append(formattedRows, separator)
+	}
+	for _, row := range rows {
+		formattedRows = append(formattedRows, layout.formatOneRow(row))
+	}
+	if bottom := layout.formatLine(style.Bottom); bottom != "" {
+		formattedRows = append(formattedRows, bottom)
+	}
+
+	return strings.Join(formattedRows, "\n")
+}
+
+// toMarkdownCells converts the cells of a row to markdown: the style tags are removed, the line breaks are replaced
+// with "<br>" and the pipes are escaped. The result is escaped again to be printed as-is.
+func toMarkdownCells(row []string) []string {
+	markdownRow := make([]string, len(row))
+	for i, cell := range row {
+		cell = strings.ReplaceAll(styledprinter.Strip(cell), "|", `\|`)
+		cell = strings.ReplaceAll(strings.ReplaceAll(cell, "\r\n", "\n"), "\n", "<br>")
+		markdownRow[i] = styledprinter.Escape(cell)
+	}
+
+	return markdownRow
+}
+
+// formatLine renders an horizontal line of the table. An empty string is returned if the line should not be printed.
+func (t tableLayout) formatLine(line TableLine) string {
+	if line == (TableLine{}) {
+		return ""
+	}
+
+	padding := t.options.style().Padding
+	formattedLine := line.Left
+	for i, width := range t.columnWidths {
+		if i > 0 {
+			formattedLine += line.Cross
+		}
+		formattedLine += strings.Repeat(line.Fill, width+2*padding)
+	}
+
+	return formattedLine + line.Right
+}
+
+// formatMarkdownSeparator renders the separator between the headers and the rows of a markdown table, with the alignment of the columns
+func (t tableLayout) formatMarkdownSeparator() string {
+	style := t.options.style()
+
+	var cells []string
+	for i, width := range t.columnWidths {
+		dashes := []byte(strings.Repeat("-", width+2*style.Padding))
+		switch t.options.column(i).Align {
+		case AlignRight:
+			dashes[len(dashes)-1] = ':'
+		case AlignCenter:
+			dashes[0] = ':'
+			dashes[len(dashes)-1] = ':'
 		}
+		cells = append(cells, string(dashes))
+	}
+
+	return style.Left + strings.Join(cells, style.Separator) + style.Right
+}
+
+func (t tableLayout) formatOneRow(row []string) string {
+	var preparedSubLines [][][]styledprinter.Segment
+	totalLines := 1
+
+	for cellIdx, width := range t.columnWidths {
+		cell := ""
+		if cellIdx < len(row) {
+			cell = row[cellIdx]
+		}
+
+		preparedCellLines := wrapCell(cell, width)
 		if len(preparedCellLines) > totalLines {
 			totalLines = len(preparedCellLines)
 		}
 		preparedSubLines = append(preparedSubLines, preparedCellLines)
 	}
 
+	style := t.options.style()
+	padding := strings.Repeat(" ", style.Padding)
+
 	var rowsToPrint []string
 	for i := 0; i < totalLines; i++ {
-		rowToPrint := "|"
+		rowToPrint := style.Left
 		for columnIdx, column := range preparedSubLines {
+			if columnIdx > 0 {
+				rowToPrint += style.Separator
+			}
 			if i < len(column) {
-				columnLen := utf8.RuneCountInString(column[i])
-				rowToPrint += fmt.Sprintf(" %s%s |", column[i], strings.Repeat(" ", columnWidths[columnIdx]-columnLen))
+				rowToPrint += padding + t.alignCellLine(column[i], columnIdx) + padding
 			} else {
-				rowToPrint += fmt.Sprintf(" %s |", strings.Repeat(" ", columnWidths[columnIdx]))
+				rowToPrint += padding + strings.Repeat(" ", t.columnWidths[columnIdx]) + padding
 			}
 		}
-		rowsToPrint = append(rowsToPrint, rowToPrint)
+		rowsToPrint = append(rowsToPrint, rowToPrint+style.Right)
 	}
 
 	return strings.Join(rowsToPrint, "\n")
@@ -3,6 +3,7 @@ package styledconsole
 import (
 	"testing"
 
+	"github.com/corentindeboisset/styledconsole/styledprinter"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,18 +35,113 @@ func TestFormatOneRow(t *testing.T) {
 
 	assert.Equal(
 		"| one     | two     | three |",
-		formatOneRow(
+		tableLayout{columnWidths: []int{7, 7, 5}}.formatOneRow(
 			[]string{"one", "two", "three"},
-			[]int{7, 7, 5},
 		),
 	)
 
 	// with UTF8
 	assert.Equal(
 		"| un      | deux    | troïs |",
-		formatOneRow(
+		tableLayout{columnWidths: []int{7, 7, 5}}.formatOneRow(
 			[]string{"un", "deux", "troïs"},
-			[]int{7, 7, 5},
 		),
 	)
 }
+
+// TestStyledCells checks the style tags are not taken into account to compute the column widths
+func TestStyledCells(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		[]int{6, 4},
+		getColumnWidths(
+			[]string{"status", "name"},
+			[][]string{{"<fg=red>FAIL</>", "<fg=blue;options=bold>test</>"}},
+		),
+	)
+
+	layout := tableLayout{columnWidths: []int{6, 4}, colorLevel: styledprinter.ColorLevel16}
+	assert.Equal("| \x1b[31mFAIL\x1b[39m   | test |", layout.formatOneRow([]string{"<fg=red>FAIL</>", "test"}))
+	assert.Equal(
+		"| \x1b[31mmulti\x1b[39m  | abcd |\n| \x1b[31mline\x1b[39m   | ef   |",
+		layout.formatOneRow([]string{"<fg=red>multi\nline</>", "abcdef"}),
+	)
+}
+
+// TestColumnOptions checks the columns can be aligned and limited in width
+func TestColumnOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	options := TableOptions{Columns: []ColumnOptions{{Align: AlignRight, MaxWidth: 4}, {Align: AlignCenter, MinWidth: 8}}}
+	assert.Equal([]int{4, 8}, applyColumnLimits([]int{6, 4}, options))
+
+	layout := tableLayout{columnWidths: []int{4, 8}, options: options}
+	assert.Equal("|   ab |   abcd   |", layout.formatOneRow([]string{"ab", "abcd"}))
+	assert.Equal("| abcd |   abc    |\n|   ef |          |", layout.formatOneRow([]string{"abcdef", "abc"}))
+
+	// The minimal width is kept even if the terminal is too small
+	assert.Equal([]int{20, 30}, getAcceptableColumnWidths([]int{40, 40}, []int{0, 30}, 40, TableStyleDefault))
+}
+
+// TestTableStyles checks the tables can be rendered with different borders
+func TestTableStyles(t *testing.T) {
+	assert := assert.New(t)
+	headers := []string{"name", "status"}
+	rows := [][]string{{"build", "<fg=green>OK</>"}, {"test|lint", "<fg=red>multi\nline</>"}}
+
+	assert.Equal(
+		"┌───────────┬────────┐\n"+
+			"│ name      │ status │\n"+
+			"├───────────┼────────┤\n"+
+			"│ build     │ OK     │\n"+
+			"│ test|lint │ multi  │\n"+
+			"│           │ line   │\n"+
+			"└───────────┴────────┘",
+		formatTable(headers, rows, TableOptions{Style: TableStyleBox}, 80, styledprinter.ColorLevelNone),
+	)
+
+	assert.Equal(
+		"name       status\n"+
+			"---------  ------\n"+
+			"build      OK    \n"+
+			"test|lint  multi \n"+
+			"           line  ",
+		formatTable(headers, rows, TableOptions{Style: TableStyleCompact}, 80, styledprinter.ColorLevelNone),
+	)
+
+	assert.Equal(
+		"| name       |        status |\n"+
+			"|------------|--------------:|\n"+
+			"| build      |            OK |\n"+
+			"| test\\|lint | multi<br>line |",
+		formatTable(
+			headers,
+			rows,
+			TableOptions{Style: TableStyleMarkdown, Columns: []ColumnOptions{{}, {Align: AlignRight}}},
+			20,
+			styledprinter.ColorLevel16,
+		),
+	)
+}
+
+// TestWideCharactersInCells checks the columns are measured on the displayed width of the cells
+func TestWideCharactersInCells(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]int{6, 2}, getColumnWidths([]string{"日本語", "👍"}, [][]string{{"abc", "ok"}}))
+
+	layout := tableLayout{columnWidths: []int{5, 2}}
+	assert.Equal("| 日本  | 👍 |\n| 語    | ok |", layout.formatOneRow([]string{"日本語", "👍\nok"}))
+}
+
+// TestWrappedCells checks the cells are broken between the words, or after a hyphen
+func TestWrappedCells(t *testing.T) {
+	assert := assert.New(t)
+
+	layout := tableLayout{columnWidths: []int{9, 6}}
+	assert.Equal(
+		"| the quick | self-  |\n| brown fox | made   |",
+		layout.formatOneRow([]string{"the quick brown fox", "self-made"}),
+	)
+}